@@ -16,6 +16,10 @@ type actionContext struct {
 	oneshot    bool
 	jiraClient *jira.Client
 	board      *Board
+	project    *Project
+	fields     fieldMap
+	filters    []fieldFilter
+	categories []string
 }
 
 // Parsed command from user
@@ -25,7 +29,14 @@ type userAction struct {
 	changeStatus  bool
 	createBranch  bool
 	createSubtask bool
+	addComment    bool
+	editIssue     bool
+	logWork       bool
+	attachFile    bool
+	assign        bool
 	listIssues    bool
+	showBoard     bool
+	createIssue   bool
 	getParents    bool
 }
 
@@ -41,7 +52,7 @@ func runInteractiveLoop(ctx *actionContext) {
 		return
 	}
 
-	displayIssues(activeIssues)
+	displayIssues(ctx, activeIssues)
 
 	for {
 		fmt.Println()
@@ -70,6 +81,26 @@ func runInteractiveLoop(ctx *actionContext) {
 			continue
 		}
 
+		if action.createIssue {
+			if err := handleCreateIssue(ctx); err != nil {
+				log.Fatalf("Action failed: %v", err)
+			}
+			if ctx.oneshot {
+				return
+			}
+			continue
+		}
+
+		if action.showBoard {
+			if err := handleShowBoard(ctx, activeIssues); err != nil {
+				log.Fatalf("Action failed: %v", err)
+			}
+			if ctx.oneshot {
+				return
+			}
+			continue
+		}
+
 		// Handle list command
 		if action.listIssues {
 			activeIssues, err = getActiveIssues(ctx)
@@ -80,7 +111,7 @@ func runInteractiveLoop(ctx *actionContext) {
 				fmt.Println("\nNo active items in this sprint")
 				return
 			}
-			displayIssues(activeIssues)
+			displayIssues(ctx, activeIssues)
 			if ctx.oneshot {
 				return
 			}
@@ -104,6 +135,16 @@ func runInteractiveLoop(ctx *actionContext) {
 			actionErr = handleChangeStatus(ctx, selectedIssue)
 		case action.createSubtask:
 			actionErr = handleCreateSubtask(ctx, selectedIssue)
+		case action.addComment:
+			actionErr = handleAddComment(ctx, selectedIssue)
+		case action.editIssue:
+			actionErr = handleEditIssue(ctx, selectedIssue)
+		case action.logWork:
+			actionErr = handleLogWork(ctx, selectedIssue)
+		case action.assign:
+			actionErr = handleAssign(ctx, selectedIssue)
+		case action.attachFile:
+			actionErr = handleAttachFile(ctx, selectedIssue)
 		default:
 			actionErr = handleShowDetails(ctx, selectedIssue)
 		}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emilsto/jig/jira"
+)
+
+// The roster of assignable users changes rarely, so it is only refetched once a day or
+// when a name doesn't match anyone
+const rosterTTL = 24 * time.Hour
+
+type roster struct {
+	Fetched time.Time   `json:"fetched"`
+	Users   []jira.User `json:"users"`
+}
+
+// rosterPath names the roster file after the project and the client's site and user, so
+// rosters of different sites or accounts are never mixed up
+func rosterPath(client *jira.Client, projectKey string) (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	scope, err := client.CacheScope(context.Background())
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "roster-"+projectKey+"-"+scope+".json"), nil
+}
+
+// loadRoster returns the project's assignable users, from the local cache when it is fresh
+func loadRoster(client *jira.Client, projectKey string, refresh bool) ([]jira.User, error) {
+	path, err := rosterPath(client, projectKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if !refresh {
+		if data, err := os.ReadFile(path); err == nil {
+			var cached roster
+			if json.Unmarshal(data, &cached) == nil && time.Since(cached.Fetched) < rosterTTL {
+				return cached.Users, nil
+			}
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	users, err := client.GetAssignableUsers(context.Background(), projectKey, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assignable users: %v", err)
+	}
+
+	data, err := json.MarshalIndent(roster{Fetched: time.Now(), Users: users}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// userMatchScore rates how well query matches a user, from 0 (no match) to 100 (exact name)
+func userMatchScore(user jira.User, query string) int {
+	name := strings.ToLower(user.DisplayName)
+	email := strings.ToLower(user.EmailAddress)
+	local, _, _ := strings.Cut(email, "@")
+
+	switch {
+	case name == query:
+		return 100
+	case local == query || email == query:
+		return 90
+	case strings.HasPrefix(name, query):
+		return 80
+	}
+	for _, word := range strings.Fields(name) {
+		if strings.HasPrefix(word, query) {
+			return 70
+		}
+	}
+	switch {
+	case strings.Contains(name, query) || strings.Contains(email, query):
+		return 50
+	case isSubsequence(query, name):
+		return 20
+	}
+	return 0
+}
+
+// isSubsequence reports whether the letters of query appear in order in text, e.g. "jdoe" in "john doe"
+func isSubsequence(query, text string) bool {
+	rest := []rune(query)
+	for _, r := range text {
+		if len(rest) == 0 {
+			break
+		}
+		if r == rest[0] {
+			rest = rest[1:]
+		}
+	}
+	return len(rest) == 0
+}
+
+// matchUsers returns users matching query, best matches first
+func matchUsers(users []jira.User, query string) []jira.User {
+	query = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(query, "@")))
+
+	type scored struct {
+		user  jira.User
+		score int
+	}
+	var matches []scored
+	for _, user := range users {
+		if score := userMatchScore(user, query); score > 0 {
+			matches = append(matches, scored{user, score})
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b scored) int { return b.score - a.score })
+
+	// A clearly better match wins outright, e.g. "ann" over "joanna"
+	if len(matches) > 1 && matches[0].score > matches[1].score && matches[0].score >= 70 {
+		matches = matches[:1]
+	}
+
+	result := make([]jira.User, len(matches))
+	for i, m := range matches {
+		result[i] = m.user
+	}
+	return result
+}
+
+// pickAssignee resolves who to assign to: "me", "none"/"-" to unassign, or a fuzzy name
+// match, prompting when several users match equally well. It returns nil to unassign
+func pickAssignee(client *jira.Client, reader *bufio.Reader, projectKey, query string) (*jira.User, error) {
+	switch strings.ToLower(strings.TrimPrefix(query, "@")) {
+	case "none", "-", "unassigned":
+		return nil, nil
+	case "me":
+		accountID, err := client.CurrentAccountID(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current user: %v", err)
+		}
+		return &jira.User{AccountID: accountID, DisplayName: "you"}, nil
+	}
+
+	users, err := loadRoster(client, projectKey, false)
+	if err != nil {
+		return nil, err
+	}
+	matches := matchUsers(users, query)
+	if len(matches) == 0 {
+		// Someone may have joined since the roster was cached
+		if users, err = loadRoster(client, projectKey, true); err != nil {
+			return nil, err
+		}
+		matches = matchUsers(users, query)
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no assignable user in %s matches %q", projectKey, query)
+	case 1:
+		return &matches[0], nil
+	}
+
+	if len(matches) > 10 {
+		matches = matches[:10]
+	}
+	fmt.Println()
+	printBold("Users matching %q:", query)
+	for i, user := range matches {
+		fmt.Printf("  %d. %s %s%s%s\n", i+1, printHighlight(user.DisplayName), colorDim, user.EmailAddress, colorReset)
+	}
+	printPrompt("Select user (number) or 0 to cancel")
+	input, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	selection, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil || selection < 0 || selection > len(matches) {
+		return nil, fmt.Errorf("invalid selection")
+	}
+	if selection == 0 {
+		return nil, errCancelled
+	}
+	return &matches[selection-1], nil
+}
+
+var errCancelled = errors.New("cancelled")
+
+// projectOf returns the project key of an issue key, e.g. PROJ for PROJ-123
+func projectOf(issueKey string) string {
+	project, _, _ := strings.Cut(issueKey, "-")
+	return project
+}
+
+func assignIssue(client *jira.Client, reader *bufio.Reader, issueKey, query string) error {
+	user, err := pickAssignee(client, reader, projectOf(issueKey), query)
+	if errors.Is(err, errCancelled) {
+		fmt.Println("Cancelled")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if user == nil {
+		if err := client.AssignIssue(context.Background(), issueKey, ""); err != nil {
+			return err
+		}
+		printSuccess("Unassigned %s", printHighlight(issueKey))
+		return nil
+	}
+
+	if err := client.AssignIssue(context.Background(), issueKey, user.AccountID); err != nil {
+		return err
+	}
+	printSuccess("Assigned %s to %s", printHighlight(issueKey), user.DisplayName)
+	return nil
+}
+
+// runAssignCommand implements `jig assign KEY @name|me|none [-refresh]`
+func runAssignCommand(args []string) error {
+	fs := flag.NewFlagSet("assign", flag.ExitOnError)
+	refresh := fs.Bool("refresh", false, "Refetch the cached list of assignable users")
+	issueKey, rest, err := parseKeyArgs(fs, args)
+	if err != nil || len(rest) == 0 {
+		return fmt.Errorf("usage: jig assign KEY @name|me|none [-refresh]")
+	}
+
+	_, client, err := loadClient()
+	if err != nil {
+		return err
+	}
+
+	if *refresh {
+		if _, err := loadRoster(client, projectOf(issueKey), true); err != nil {
+			return err
+		}
+	}
+
+	return assignIssue(client, bufio.NewReader(os.Stdin), issueKey, strings.Join(rest, " "))
+}
+
+func handleAssign(ctx *actionContext, issue jira.Issue) error {
+	printPrompt("Assign to (name, 'me', or '-' to unassign)")
+	input, err := ctx.reader.ReadString('\n')
+	if err != nil {
+		return err
+	}
+
+	query := strings.TrimSpace(input)
+	if query == "" {
+		fmt.Println("Cancelled")
+		return nil
+	}
+	return assignIssue(ctx.jiraClient, ctx.reader, issue.Key, query)
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/emilsto/jig/jira"
+)
+
+// Transfers smaller than this finish too quickly to be worth a progress bar
+const progressThreshold = 1 << 20
+
+// formatSize renders a byte count in human readable units
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+// newProgress returns a progress callback that redraws a bar for large transfers
+func newProgress(label string, size int64) jira.ProgressFunc {
+	if size < progressThreshold {
+		return nil
+	}
+
+	const width = 30
+	lastPercent := -1
+	return func(done, total int64) {
+		if total <= 0 {
+			return
+		}
+		percent := int(done * 100 / total)
+		if percent == lastPercent {
+			return
+		}
+		lastPercent = percent
+
+		filled := width * percent / 100
+		fmt.Printf("\r  %s %s[%s%s]%s %3d%% %s/%s",
+			truncate(label, 30), colorCyan, strings.Repeat("█", filled), strings.Repeat(" ", width-filled), colorReset,
+			percent, formatSize(done), formatSize(total))
+		if done >= total {
+			fmt.Println()
+		}
+	}
+}
+
+func printAttachments(attachments []jira.Attachment) {
+	for i, a := range attachments {
+		fmt.Printf("  %s%2d.%s %-40s %10s  %s%s, %s%s\n",
+			colorDim, i+1, colorReset, a.Filename, formatSize(a.Size),
+			colorDim, a.Author.DisplayName, formatDate(a.Created), colorReset)
+	}
+}
+
+// formatDate trims a Jira timestamp down to its date
+func formatDate(timestamp string) string {
+	if len(timestamp) >= 10 {
+		return timestamp[:10]
+	}
+	return timestamp
+}
+
+// runAttachmentsCommand implements `jig attachments KEY`
+func runAttachmentsCommand(args []string) error {
+	fs := flag.NewFlagSet("attachments", flag.ExitOnError)
+	issueKey, _, err := parseWithKey(fs, args)
+	if err != nil {
+		return fmt.Errorf("usage: jig attachments KEY")
+	}
+
+	_, client, err := loadClient()
+	if err != nil {
+		return err
+	}
+
+	issue, err := client.GetIssueDetails(context.Background(), issueKey)
+	if err != nil {
+		return err
+	}
+
+	if len(issue.Fields.Attachment) == 0 {
+		fmt.Printf("No attachments on %s\n", issueKey)
+		return nil
+	}
+
+	fmt.Println()
+	printBold("Attachments on %s (%d):", issueKey, len(issue.Fields.Attachment))
+	printAttachments(issue.Fields.Attachment)
+	return nil
+}
+
+// runDownloadCommand implements `jig download KEY [name|number...] [-all] [-o dir]`
+func runDownloadCommand(args []string) error {
+	fs := flag.NewFlagSet("download", flag.ExitOnError)
+	all := fs.Bool("all", false, "Download every attachment")
+	dir := fs.String("o", ".", "Directory to save into")
+
+	issueKey, names, err := parseKeyArgs(fs, args)
+	if err != nil || (len(names) == 0 && !*all) {
+		return fmt.Errorf("usage: jig download KEY [filename|number...] [-all] [-o dir]")
+	}
+
+	_, client, err := loadClient()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	issue, err := client.GetIssueDetails(ctx, issueKey)
+	if err != nil {
+		return err
+	}
+	available := issue.Fields.Attachment
+
+	var selected []jira.Attachment
+	if *all {
+		selected = available
+	} else {
+		for _, name := range names {
+			attachment, err := findAttachment(available, name)
+			if err != nil {
+				return err
+			}
+			selected = append(selected, attachment)
+		}
+	}
+
+	if len(selected) == 0 {
+		fmt.Printf("No attachments on %s\n", issueKey)
+		return nil
+	}
+
+	if err := os.MkdirAll(*dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %v", *dir, err)
+	}
+
+	used := map[string]bool{}
+	for _, attachment := range selected {
+		// Never trust server-provided names with path components
+		name := filepath.Base(attachment.Filename)
+		if used[name] {
+			// Attachments often share a name, e.g. screenshot.png; keep each of them
+			ext := filepath.Ext(name)
+			name = strings.TrimSuffix(name, ext) + "-" + attachment.ID + ext
+		}
+		used[name] = true
+
+		path := filepath.Join(*dir, name)
+		if err := downloadAttachment(ctx, client, attachment, path); err != nil {
+			return err
+		}
+		printSuccess("Saved %s (%s)", printHighlight(path), formatSize(attachment.Size))
+	}
+	return nil
+}
+
+func downloadAttachment(ctx context.Context, client *jira.Client, attachment jira.Attachment, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", path, err)
+	}
+
+	err = client.DownloadAttachment(ctx, attachment, file, newProgress(attachment.Filename, attachment.Size))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return fmt.Errorf("failed to download %s: %v", attachment.Filename, err)
+	}
+	return nil
+}
+
+// findAttachment matches by list number, attachment ID or filename
+func findAttachment(attachments []jira.Attachment, name string) (jira.Attachment, error) {
+	if num, err := strconv.Atoi(name); err == nil && num >= 1 && num <= len(attachments) {
+		return attachments[num-1], nil
+	}
+	for _, a := range attachments {
+		if a.ID == name || a.Filename == name {
+			return a, nil
+		}
+	}
+	return jira.Attachment{}, fmt.Errorf("no attachment %q", name)
+}
+
+// runAttachCommand implements `jig attach KEY FILE...`
+func runAttachCommand(args []string) error {
+	fs := flag.NewFlagSet("attach", flag.ExitOnError)
+	issueKey, files, err := parseWithKey(fs, args)
+	if err != nil || len(files) == 0 {
+		return fmt.Errorf("usage: jig attach KEY FILE...")
+	}
+
+	_, client, err := loadClient()
+	if err != nil {
+		return err
+	}
+
+	for _, path := range files {
+		if err := uploadAttachment(client, issueKey, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func uploadAttachment(client *jira.Client, issueKey, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if stat.IsDir() {
+		return fmt.Errorf("%s is a directory", path)
+	}
+
+	name := filepath.Base(path)
+	_, err = client.UploadAttachment(context.Background(), issueKey, name, file, stat.Size(), newProgress(name, stat.Size()))
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %v", name, err)
+	}
+
+	printSuccess("Attached %s (%s) to %s", name, formatSize(stat.Size()), printHighlight(issueKey))
+	return nil
+}
+
+func handleAttachFile(ctx *actionContext, issue jira.Issue) error {
+	printPrompt("File(s) to attach (space-separated paths)")
+	input, err := ctx.reader.ReadString('\n')
+	if err != nil {
+		return err
+	}
+
+	paths := strings.Fields(input)
+	if len(paths) == 0 {
+		fmt.Println("Cancelled")
+		return nil
+	}
+
+	for _, path := range paths {
+		if err := uploadAttachment(ctx.jiraClient, issue.Key, expandHome(path)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expandHome resolves a leading ~ as typed at an interactive prompt
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[1:])
+		}
+	}
+	return path
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/emilsto/jig/jira"
+)
+
+const backlogUsage = "usage: jig backlog [KEY... above|below KEY | KEY... estimate N | KEY... pull [current|next|SPRINT]]"
+
+// backlogSession holds what grooming actions need between refreshes of the backlog
+type backlogSession struct {
+	client     *jira.Client
+	board      *Board
+	pointsID   string
+	issues     []jira.Issue
+	sprints    []jira.Sprint
+	sprintsSet bool
+}
+
+// runBacklogCommand implements `jig backlog`: without arguments it shows the backlog and
+// accepts grooming actions until q, otherwise it runs the single action given
+func runBacklogCommand(args []string) error {
+	config, client, err := loadClient()
+	if err != nil {
+		return err
+	}
+
+	_, board, err := selectProjectAndBoard(config)
+	if err != nil {
+		return err
+	}
+
+	session := &backlogSession{
+		client:   client,
+		board:    board,
+		pointsID: loadFieldMap(config, client)["story_points"],
+	}
+
+	if len(args) > 0 {
+		return session.runOnce(args)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		if err := session.refresh(); err != nil {
+			return err
+		}
+		session.print()
+
+		fmt.Println()
+		printDim("Actions: 3 above 1 | 3 below PROJ-7 | 3 est 5 | 3 4 pull [next] | q to quit")
+		printPrompt("Groom")
+		input, err := reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+
+		fields := strings.Fields(strings.ReplaceAll(input, ",", " "))
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "q" || fields[0] == "0" {
+			return nil
+		}
+		if err := session.run(fields); err != nil {
+			printError("%v", err)
+		}
+	}
+}
+
+func (s *backlogSession) refresh() error {
+	fields := []string{"summary", "status", "assignee", "issuetype", "labels"}
+	if s.pointsID != "" {
+		fields = append(fields, s.pointsID)
+	}
+
+	printInfo("Fetching backlog for %s...", s.board.Name)
+	issues, err := s.client.GetBacklog(context.Background(), s.board.ID, fields...)
+	if err != nil {
+		return fmt.Errorf("failed to get backlog: %v", err)
+	}
+	s.issues = issues
+	return nil
+}
+
+func (s *backlogSession) print() {
+	fmt.Println()
+	printBold("Backlog (%d):", len(s.issues))
+	if len(s.issues) == 0 {
+		printDim("  Nothing in the backlog")
+		return
+	}
+
+	fmt.Printf("%s%4s  %-12s %-8s %4s  %-50s %s%s\n", colorBold, "#", "KEY", "TYPE", "EST", "SUMMARY", "LABELS", colorReset)
+	for i, issue := range s.issues {
+		estimate := formatFieldValue(issue.CustomFields[s.pointsID])
+		if estimate == "" {
+			estimate = colorDim + fmt.Sprintf("%4s", "·") + colorReset
+		} else {
+			estimate = fmt.Sprintf("%4s", estimate)
+		}
+		fmt.Printf("%4d  %s%-12s%s %-8s %s  %-50s %s%s%s\n",
+			i+1, colorCyan, issue.Key, colorReset,
+			truncate(issue.Fields.IssueType.Name, 8), estimate,
+			truncate(issue.Fields.Summary, 50),
+			colorDim, strings.Join(issue.Fields.Labels, ", "), colorReset)
+	}
+}
+
+// resolve turns a backlog position or an issue key into a key
+func (s *backlogSession) resolve(ref string) (string, error) {
+	if num, err := strconv.Atoi(ref); err == nil {
+		if num < 1 || num > len(s.issues) {
+			return "", fmt.Errorf("no backlog item %d", num)
+		}
+		return s.issues[num-1].Key, nil
+	}
+	if !strings.Contains(ref, "-") {
+		return "", fmt.Errorf("%q is neither a number nor an issue key", ref)
+	}
+	return strings.ToUpper(ref), nil
+}
+
+// backlogVerb returns the position of the action in `REF... VERB [ARG]`, or -1
+func backlogVerb(args []string) int {
+	return slices.IndexFunc(args, func(arg string) bool {
+		return slices.Contains([]string{"above", "below", "estimate", "est", "pull"}, strings.ToLower(arg))
+	})
+}
+
+// refersByPosition reports whether an action names backlog items by number, which can
+// only be resolved once the backlog has been fetched
+func refersByPosition(args []string) bool {
+	verbAt := backlogVerb(args)
+	if verbAt < 0 {
+		return false
+	}
+	refs := slices.Clone(args[:verbAt])
+	if verb := strings.ToLower(args[verbAt]); (verb == "above" || verb == "below") && len(args) > verbAt+1 {
+		refs = append(refs, args[verbAt+1])
+	}
+	for _, ref := range refs {
+		if _, err := strconv.Atoi(ref); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// runOnce runs a single action given on the command line, fetching the backlog first
+// when the action refers to items by position
+func (s *backlogSession) runOnce(args []string) error {
+	if refersByPosition(args) {
+		if err := s.refresh(); err != nil {
+			return err
+		}
+	}
+	return s.run(args)
+}
+
+// run executes `REF... VERB [ARG]`, where REF is a backlog position or an issue key
+func (s *backlogSession) run(args []string) error {
+	verbAt := backlogVerb(args)
+	if verbAt < 1 {
+		return fmt.Errorf(backlogUsage)
+	}
+
+	keys := make([]string, 0, verbAt)
+	for _, ref := range args[:verbAt] {
+		key, err := s.resolve(ref)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+	rest := args[verbAt+1:]
+
+	ctx := context.Background()
+	switch verb := strings.ToLower(args[verbAt]); verb {
+	case "above", "below":
+		if len(rest) != 1 {
+			return fmt.Errorf("usage: KEY... %s KEY", verb)
+		}
+		other, err := s.resolve(rest[0])
+		if err != nil {
+			return err
+		}
+		if err := s.client.RankIssues(ctx, keys, other, verb == "above"); err != nil {
+			return err
+		}
+		printSuccess("Ranked %s %s %s", printHighlight(strings.Join(keys, ", ")), verb, printHighlight(other))
+
+	case "estimate", "est":
+		if len(rest) != 1 {
+			return fmt.Errorf("usage: KEY... estimate N")
+		}
+		for _, key := range keys {
+			if err := s.client.SetEstimate(ctx, s.board.ID, key, rest[0]); err != nil {
+				return fmt.Errorf("failed to estimate %s: %v", key, err)
+			}
+			printSuccess("Estimated %s at %s", printHighlight(key), rest[0])
+		}
+
+	case "pull":
+		ref := "current"
+		if len(rest) > 0 {
+			ref = strings.Join(rest, " ")
+		}
+		if !s.sprintsSet {
+			sprints, err := s.client.GetBoardSprints(ctx, s.board.ID, "active", "future")
+			if err != nil {
+				return fmt.Errorf("failed to get sprints: %v", err)
+			}
+			s.sprints, s.sprintsSet = sprints, true
+		}
+		sprint, err := findSprint(s.sprints, ref)
+		if err != nil {
+			return err
+		}
+		if err := s.client.MoveIssuesToSprint(ctx, sprint.ID, keys); err != nil {
+			return err
+		}
+		printSuccess("Pulled %s into %s", printHighlight(strings.Join(keys, ", ")), sprint.Name)
+	}
+	return nil
+}
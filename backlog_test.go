@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/emilsto/jig/jira"
+)
+
+func TestRefersByPosition(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"3", "above", "1"}, true},
+		{[]string{"PROJ-3", "above", "1"}, true},
+		{[]string{"PROJ-3", "below", "PROJ-1"}, false},
+		{[]string{"PROJ-3", "estimate", "5"}, false},
+		{[]string{"2", "4", "pull", "next"}, true},
+		{[]string{"PROJ-2", "pull", "3"}, false},
+		{[]string{"3"}, false},
+	}
+	for _, tt := range tests {
+		if got := refersByPosition(tt.args); got != tt.want {
+			t.Errorf("refersByPosition(%q) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestBacklogRunOnce(t *testing.T) {
+	var ranked map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/agile/board/7/backlog":
+			w.Write([]byte(`{"startAt": 0, "total": 3, "issues": [{"key": "PROJ-10"}, {"key": "PROJ-11"}, {"key": "PROJ-12"}]}`))
+		case "/agile/issue/rank":
+			json.NewDecoder(r.Body).Decode(&ranked)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := jira.NewClient(jira.Config{BaseURL: srv.URL + "/api", AgileURL: srv.URL + "/agile", Flavor: jira.Cloud})
+	if err != nil {
+		t.Fatal(err)
+	}
+	session := &backlogSession{client: client, board: &Board{Name: "Team", ID: 7}}
+
+	// jig backlog 3 above 1
+	if err := session.runOnce([]string{"3", "above", "1"}); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(ranked["issues"]) != "[PROJ-12]" || ranked["rankBeforeIssue"] != "PROJ-10" {
+		t.Errorf("ranked %v, want PROJ-12 before PROJ-10", ranked)
+	}
+
+	for ref, want := range map[string]string{"1": "PROJ-10", "3": "PROJ-12", "proj-5": "PROJ-5"} {
+		if got, err := session.resolve(ref); err != nil || got != want {
+			t.Errorf("resolve(%q) = %q, %v; want %q", ref, got, err, want)
+		}
+	}
+	if _, err := session.resolve("4"); err == nil {
+		t.Errorf("resolve(\"4\") succeeded with a 3 item backlog")
+	}
+}
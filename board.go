@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/emilsto/jig/jira"
+)
+
+const (
+	minColumnWidth = 18
+	columnGap      = 2
+)
+
+// terminalWidth returns the width of the terminal, falling back to 100 columns
+func terminalWidth() int {
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+
+	cmd := exec.Command("stty", "size")
+	cmd.Stdin = os.Stdin
+	if out, err := cmd.Output(); err == nil {
+		if fields := strings.Fields(string(out)); len(fields) == 2 {
+			if columns, err := strconv.Atoi(fields[1]); err == nil && columns > 0 {
+				return columns
+			}
+		}
+	}
+	return 100
+}
+
+// padVisible pads text with spaces to width, ignoring colour codes
+func padVisible(text string, width int) string {
+	if pad := width - len([]rune(stripColors(text))); pad > 0 {
+		return text + strings.Repeat(" ", pad)
+	}
+	return text
+}
+
+func stripColors(text string) string {
+	for _, code := range []string{colorReset, colorRed, colorGreen, colorYellow, colorBlue, colorCyan, colorBold, colorDim} {
+		text = strings.ReplaceAll(text, code, "")
+	}
+	return text
+}
+
+// boardCard renders an issue as the lines of a card; num is its number in the
+// interactive list, or 0 when it isn't selectable
+func boardCard(issue jira.Issue, num, width int) []string {
+	key := issue.Key
+	if isBlocked(issue) {
+		key += " ⚠"
+	}
+	head := colorCyan + truncate(key, width) + colorReset
+	if num > 0 {
+		head = fmt.Sprintf("%s%d.%s %s", colorDim, num, colorReset, colorCyan+truncate(key, width-len(strconv.Itoa(num))-2)+colorReset)
+	}
+
+	assignee := issue.Fields.Assignee.DisplayName
+	if assignee == "" {
+		assignee = "Unassigned"
+	}
+	return []string{
+		head,
+		truncate(issue.Fields.Summary, width),
+		colorDim + truncate(assignee, width) + colorReset,
+	}
+}
+
+// displayBoard renders issues in the board's columns side by side, wrapping onto further
+// rows of columns when the terminal is too narrow
+func displayBoard(board *jira.BoardConfiguration, issues []jira.Issue, numbers map[string]int) {
+	columns := board.ColumnConfig.Columns
+	cards := make([][]jira.Issue, len(columns))
+	unmapped := 0
+	for _, issue := range issues {
+		placed := false
+		for i, column := range columns {
+			if column.HasStatus(issue.Fields.Status.ID) {
+				cards[i] = append(cards[i], issue)
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			unmapped++
+		}
+	}
+
+	width := terminalWidth()
+	perRow := max(1, min(len(columns), (width+columnGap)/(minColumnWidth+columnGap)))
+	colWidth := (width+columnGap)/perRow - columnGap
+
+	for start := 0; start < len(columns); start += perRow {
+		end := min(start+perRow, len(columns))
+
+		fmt.Println()
+		var header, rule []string
+		for i := start; i < end; i++ {
+			title := truncate(fmt.Sprintf("%s (%d)", strings.ToUpper(columns[i].Name), len(cards[i])), colWidth)
+			header = append(header, colorBold+padVisible(title, colWidth)+colorReset)
+			rule = append(rule, colorDim+strings.Repeat("─", colWidth)+colorReset)
+		}
+		gap := strings.Repeat(" ", columnGap)
+		fmt.Println(strings.TrimRight(strings.Join(header, gap), " "))
+		fmt.Println(strings.Join(rule, gap))
+
+		var lines [][]string
+		height := 0
+		for i := start; i < end; i++ {
+			var column []string
+			for j, issue := range cards[i] {
+				if j > 0 {
+					column = append(column, "")
+				}
+				column = append(column, boardCard(issue, numbers[issue.Key], colWidth)...)
+			}
+			lines = append(lines, column)
+			height = max(height, len(column))
+		}
+
+		for row := 0; row < height; row++ {
+			cells := make([]string, len(lines))
+			for i, column := range lines {
+				cell := ""
+				if row < len(column) {
+					cell = column[row]
+				}
+				cells[i] = padVisible(cell, colWidth)
+			}
+			fmt.Println(strings.TrimRight(strings.Join(cells, gap), " "))
+		}
+	}
+
+	if unmapped > 0 {
+		fmt.Println()
+		printDim("%d issue(s) have a status that isn't mapped to a column", unmapped)
+	}
+}
+
+// runBoardCommand implements `jig board [-sprint SPRINT]`
+func runBoardCommand(args []string) error {
+	fs := flag.NewFlagSet("board", flag.ExitOnError)
+	sprintRef := fs.String("sprint", "", "Sprint to show: name, ID, current, next or previous")
+	fs.Parse(args)
+
+	config, client, err := loadClient()
+	if err != nil {
+		return err
+	}
+
+	_, board, err := selectProjectAndBoard(config)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	boardConfig, err := client.GetBoardConfiguration(ctx, board.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get board configuration: %v", err)
+	}
+
+	var issues []jira.Issue
+	if boardConfig.Type == "kanban" {
+		// Kanban boards have no sprints; leave out work finished long ago
+		issues, err = client.GetBoardIssues(ctx, board.ID, "statusCategory != Done OR resolved >= -14d")
+		if err != nil {
+			return err
+		}
+	} else {
+		sprint, err := selectSprint(client, board, bufio.NewReader(os.Stdin), *sprintRef)
+		if err != nil {
+			return err
+		}
+		if sprint == nil {
+			return fmt.Errorf("no active or future sprints found")
+		}
+		printBold("%s", sprint.Name)
+		issues, err = client.GetSprintIssues(ctx, sprint.ID, config.IssueTypes...)
+		if err != nil {
+			return err
+		}
+	}
+
+	displayBoard(boardConfig, issues, nil)
+	return nil
+}
+
+// handleShowBoard shows the current sprint as a board, numbering the cards like the list
+func handleShowBoard(ctx *actionContext, activeIssues []jira.Issue) error {
+	background := context.Background()
+	boardConfig, err := ctx.jiraClient.GetBoardConfiguration(background, ctx.board.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get board configuration: %v", err)
+	}
+
+	issues, err := ctx.jiraClient.GetSprintIssues(background, ctx.sprint.ID, ctx.config.IssueTypes...)
+	if err != nil {
+		return err
+	}
+
+	numbers := map[string]int{}
+	for i, issue := range activeIssues {
+		numbers[issue.Key] = i + 1
+	}
+
+	var shown []jira.Issue
+	for _, issue := range issues {
+		if matchesFilters(issue, ctx.filters) {
+			shown = append(shown, issue)
+		}
+	}
+
+	displayBoard(boardConfig, shown, numbers)
+	return nil
+}
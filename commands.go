@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/emilsto/jig/jira"
+)
+
+// loadClient loads the global config and creates a Jira client for one-off commands
+func loadClient() (*Config, *jira.Client, error) {
+	config, err := getOrCreateConfig("config.toml")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client, err := newJiraClient(config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create Jira client: %v", err)
+	}
+
+	return config, client, nil
+}
+
+// runExportCommand prints an issue as Markdown, e.g. for pasting into a PR description
+func runExportCommand(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	output := fs.String("out", "", "Write Markdown to file instead of stdout")
+	issueKey, _, err := parseWithKey(fs, args)
+	if err != nil {
+		return fmt.Errorf("usage: jig export KEY [-out file.md]")
+	}
+
+	_, client, err := loadClient()
+	if err != nil {
+		return err
+	}
+
+	issue, err := client.GetIssueDetails(context.Background(), issueKey)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s: %s\n\n", issue.Key, issue.Fields.Summary)
+	fmt.Fprintf(&b, "- **Type:** %s\n", issue.Fields.IssueType.Name)
+	fmt.Fprintf(&b, "- **Status:** %s\n", issue.Fields.Status.Name)
+	if issue.Fields.Assignee.DisplayName != "" {
+		fmt.Fprintf(&b, "- **Assignee:** %s\n", issue.Fields.Assignee.DisplayName)
+	}
+	if issue.Fields.Priority.Name != "" {
+		fmt.Fprintf(&b, "- **Priority:** %s\n", issue.Fields.Priority.Name)
+	}
+	if len(issue.Fields.Labels) > 0 {
+		fmt.Fprintf(&b, "- **Labels:** %s\n", strings.Join(issue.Fields.Labels, ", "))
+	}
+
+	if description := jira.RenderADFMarkdown(issue.Fields.Description); description != "" {
+		b.WriteString("\n" + description + "\n")
+	}
+
+	if *output == "" {
+		fmt.Print(b.String())
+		return nil
+	}
+
+	if err := os.WriteFile(*output, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", *output, err)
+	}
+	printSuccess("Exported %s to %s", printHighlight(issue.Key), printHighlight(*output))
+	return nil
+}
+
+// runCommentCommand adds a Markdown comment to an issue from a file, stdin or $EDITOR
+func runCommentCommand(args []string) error {
+	fs := flag.NewFlagSet("comment", flag.ExitOnError)
+	file := fs.String("f", "", "Read Markdown from file ('-' for stdin) instead of $EDITOR")
+	issueKey, _, err := parseWithKey(fs, args)
+	if err != nil {
+		return fmt.Errorf("usage: jig comment KEY [-f file.md]")
+	}
+
+	_, client, err := loadClient()
+	if err != nil {
+		return err
+	}
+
+	body, err := readMarkdown(*file, "")
+	if err != nil {
+		return err
+	}
+	if body == "" {
+		fmt.Println("Empty comment, cancelled")
+		return nil
+	}
+
+	if err := client.AddComment(context.Background(), issueKey, jira.MarkdownToADF(body)); err != nil {
+		return err
+	}
+
+	printSuccess("Comment added to %s", printHighlight(issueKey))
+	return nil
+}
+
+// runDescribeCommand replaces an issue's description, opening the current one in $EDITOR as Markdown
+func runDescribeCommand(args []string) error {
+	fs := flag.NewFlagSet("describe", flag.ExitOnError)
+	file := fs.String("f", "", "Read Markdown from file ('-' for stdin) instead of $EDITOR")
+	issueKey, _, err := parseWithKey(fs, args)
+	if err != nil {
+		return fmt.Errorf("usage: jig describe KEY [-f file.md]")
+	}
+
+	_, client, err := loadClient()
+	if err != nil {
+		return err
+	}
+
+	current := ""
+	if *file == "" {
+		issue, err := client.GetIssueDetails(context.Background(), issueKey)
+		if err != nil {
+			return err
+		}
+		current = jira.RenderADFMarkdown(issue.Fields.Description) + "\n"
+	}
+
+	body, err := readMarkdown(*file, current)
+	if err != nil {
+		return err
+	}
+	if body == strings.TrimSpace(current) {
+		fmt.Println("Description unchanged")
+		return nil
+	}
+
+	if err := client.SetDescription(context.Background(), issueKey, jira.MarkdownToADF(body)); err != nil {
+		return err
+	}
+
+	printSuccess("Description of %s updated", printHighlight(issueKey))
+	return nil
+}
+
+// runCacheCommand implements `jig cache clear` and `jig cache path`
+func runCacheCommand(args []string) error {
+	dir, err := cacheDir()
+	if err != nil {
+		return err
+	}
+
+	if len(args) != 1 {
+		return fmt.Errorf("usage: jig cache clear|path")
+	}
+
+	switch args[0] {
+	case "path":
+		fmt.Println(dir)
+	case "clear":
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("failed to clear cache: %v", err)
+		}
+		printSuccess("Cleared %s", printHighlight(dir))
+	default:
+		return fmt.Errorf("unknown cache command %q; usage: jig cache clear|path", args[0])
+	}
+	return nil
+}
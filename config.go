@@ -2,11 +2,15 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -14,11 +18,33 @@ import (
 	"github.com/emilsto/jig/jira"
 )
 
+// jigrcVersion is the .jigrc schema this jig writes; files without a version are version 1,
+// which only held the project, board and sprint
+const jigrcVersion = 2
+
 type JigRC struct {
+	// Version is the schema version, so later releases can migrate older files
+	Version     int    `toml:"version"`
 	ProjectName string `toml:"project_name"`
 	ProjectID string `toml:"project_id"`
 	BoardName string `toml:"board_name"`
 	BoardID int `toml:"board_id"`
+	SprintName string `toml:"sprint_name,omitempty"`
+	SprintID int `toml:"sprint_id,omitempty"`
+	// StatusCategories limits the sprint list to these categories (keys or names, e.g.
+	// "new", "indeterminate", "In Progress"); everything not done is shown when empty
+	StatusCategories []string `toml:"status_categories,omitempty"`
+	// Profile picks one of the [profiles.<name>] in config.toml for this repository
+	Profile string `toml:"profile,omitempty"`
+
+	// The team conventions below are merged over config.toml, see applyJigRC
+	StartStatus string              `toml:"start_status,omitempty"`
+	IssueTypes  []string            `toml:"issue_types,omitempty"`
+	Git         GitConfig           `toml:"git,omitempty"`
+	Table       TableConfig         `toml:"table,omitempty"`
+	Filters     map[string][]string `toml:"filters,omitempty"`
+	// Hooks only apply to the repository, so they live in .jigrc alone
+	Hooks HooksConfig `toml:"hooks,omitempty"`
 }
 
 type Board struct {
@@ -32,20 +58,100 @@ type Project struct {
 	Boards []Board
 }
 
+type APIConfig struct {
+	Apikey string `toml:"apikey"`
+	Baseurl string `toml:"baseurl"`
+	Agileurl string `toml:"agileurl"`
+	Email string `toml:"email"`
+	// Flavor is "cloud" or "server" (Server and Data Center); detected when empty
+	Flavor string `toml:"flavor,omitempty"`
+	// Auth is "basic" (email and API token), "pat" (a Data Center personal access token)
+	// or "oauth" (see jig auth login --oauth); without an email it defaults to pat
+	Auth string `toml:"auth,omitempty"`
+	// OAuthClientID and CloudID identify the OAuth app and the site it was granted access to
+	OAuthClientID string `toml:"oauth_client_id,omitempty"`
+	CloudID string `toml:"cloud_id,omitempty"`
+	// CredentialStore keeps the token out of this file: "keyring" or "pass"
+	CredentialStore string `toml:"credential_store,omitempty"`
+	// CredentialCommand prints the token on stdout, e.g. "op read op://work/jira/token"
+	CredentialCommand string `toml:"credential_command,omitempty"`
+}
+
+type GitConfig struct {
+	Branchbase string `toml:"branchbase"`
+	// BranchTemplate names new branches; {branchbase}, {key}, {type} and {summary} are
+	// filled in, and the default is "{branchbase}/{key}/{summary}"
+	BranchTemplate string `toml:"branch_template,omitempty"`
+	// CommitFormat is how the prepare-commit-msg hook rewrites a commit message; {key} and
+	// {message} are filled in, and the default is "{key}: {message}"
+	CommitFormat string `toml:"commit_format,omitempty"`
+}
+
+type TableConfig struct {
+	Columns []string `toml:"columns,omitempty"`
+}
+
+// HooksConfig selects the git hooks jig installs, see jig hooks install
+type HooksConfig struct {
+	// PrepareCommitMsg formats commit messages with the issue key from the branch name
+	PrepareCommitMsg bool `toml:"prepare_commit_msg,omitempty"`
+	// CommitMsg rejects commit messages that don't mention an issue key
+	CommitMsg bool `toml:"commit_msg,omitempty"`
+}
+
+// Profile is another Jira site or account; whatever it sets replaces the top-level values
+type Profile struct {
+	Api      APIConfig         `toml:"api"`
+	Git      GitConfig         `toml:"git,omitempty"`
+	Projects []Project         `toml:"projects,omitempty"`
+	Fields   map[string]string `toml:"fields,omitempty"`
+}
+
 type Config struct {
-	Api struct {
-		Apikey string `toml:"apikey"`
-		Baseurl string `toml:"baseurl"`
-		Agileurl string `toml:"agileurl"`
-		Email string `toml:"email"`
-	} `toml:"api"`
-	Git struct {
-		Branchbase string `toml:"branchbase"`
-	} `toml:"git"`
+	// Profile is the profile used when neither --profile, JIG_PROFILE nor .jigrc name one
+	Profile  string              `toml:"profile,omitempty"`
+	Api      APIConfig           `toml:"api"`
+	Git      GitConfig           `toml:"git"`
+	Profiles map[string]*Profile `toml:"profiles,omitempty"`
 	Projects []Project `toml:"projects"`
+	// Fields maps friendly names such as story_points to custom field IDs
+	Fields map[string]string `toml:"fields,omitempty"`
+	Table  TableConfig       `toml:"table"`
+	// StartStatus is the status issues move to when a branch is created for them
+	StartStatus string `toml:"start_status,omitempty"`
+	// IssueTypes limits the sprint list to these issue types; all are shown when empty
+	IssueTypes []string `toml:"issue_types,omitempty"`
+	// Filters are named sets of field=value conditions, selected with -filter NAME
+	Filters map[string][]string `toml:"filters,omitempty"`
+
+	// project and board select what to work on, by ID or name; they are resolved from
+	// .jigrc, the environment and flags rather than stored in config.toml
+	project string
+	board   string
+	// activeProfile is the profile in use, see resolveConfig
+	activeProfile string
+	// origins records where each resolved setting came from, see resolveConfig
+	origins map[string]string
+	// file is config.toml as loaded from path, before profiles and overrides were applied;
+	// it is what saveConfig writes
+	file *Config
+	path string
+	// jigrcs are the .jigrc files merged into this config, outermost first
+	jigrcs []string
+	// tokenErr is why the token couldn't be read from the credential store, if it couldn't
+	tokenErr error
 }
 
+// findConfig returns the config file given with --config or JIG_CONFIG, or else the first
+// one found in the current directory, next to the executable or in the config directory
 func findConfig(filename string) string {
+	if path := explicitConfigPath(); path != "" {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		return ""
+	}
+
 	if _, err := os.Stat(filename); err == nil {
 		return filename
 	}
@@ -58,6 +164,14 @@ func findConfig(filename string) string {
 		}
 	}
 
+	if dir, err := configDir(); err == nil {
+		configPath := filepath.Join(dir, filename)
+		if _, err := os.Stat(configPath); err == nil {
+			return configPath
+		}
+	}
+
+	// Configs from before XDG_CONFIG_HOME was honoured
 	if homeDir, err := os.UserHomeDir(); err == nil {
 		configPath := filepath.Join(homeDir, ".config", "jig", filename)
 		if _, err := os.Stat(configPath); err == nil {
@@ -68,6 +182,42 @@ func findConfig(filename string) string {
 	return ""
 }
 
+// explicitConfigPath returns the config file named with --config or JIG_CONFIG, if any
+func explicitConfigPath() string {
+	if configFlag != "" {
+		return configFlag
+	}
+	return os.Getenv("JIG_CONFIG")
+}
+
+// newConfigPath returns where a config created on first run is written
+func newConfigPath(filename string) (string, error) {
+	if path := explicitConfigPath(); path != "" {
+		return path, nil
+	}
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, filename), nil
+}
+
+// checkConfigPermissions returns an error when a config file, which may hold a token,
+// can be read by other users
+func checkConfigPermissions(path string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.Mode().Perm()&0004 != 0 {
+		return fmt.Errorf("%s is readable by every user (mode %04o); run chmod 600 %s", path, info.Mode().Perm(), path)
+	}
+	return nil
+}
+
 func loadConfig(filename string) (*Config, error) {
 	file, err := os.Open(filename)
 	if err != nil {
@@ -99,48 +249,89 @@ func promptForConfig() (*Config, error) {
 	reader := bufio.NewReader(os.Stdin)
 	config := &Config{}
 
-	printWarning("No config file found.")
-	fmt.Println()
-
-	printPrompt("Enter Jira API Key")
-	apikey, err := reader.ReadString('\n')
+	fmt.Printf("%sEnter Jira Company Name or Server URL %s(e.g., yourcompany → https://yourcompany.atlassian.net, or https://jira.example.com)%s:%s ", colorYellow, colorDim, colorYellow, colorReset)
+	site, err := reader.ReadString('\n')
 	if err != nil {
 		return nil, err
 	}
-	config.Api.Apikey = strings.TrimSpace(apikey)
+	site = strings.TrimRight(strings.TrimSpace(site), "/")
+	if !strings.Contains(site, "://") {
+		site = fmt.Sprintf("https://%s.atlassian.net", site)
+	}
+
+	config.Api.Flavor = string(detectFlavor(site))
+	if config.Api.Flavor == string(jira.Server) {
+		printDim("Jira Server / Data Center detected; create a personal access token under Profile → Personal Access Tokens")
+		config.Api.Baseurl = site + "/rest/api/2"
+		config.Api.Auth = "pat"
+
+		apikey, err := readSecret(reader, "Enter Personal Access Token")
+		if err != nil {
+			return nil, err
+		}
+		config.Api.Apikey = apikey
+	} else {
+		config.Api.Baseurl = site + "/rest/api/3"
+
+		apikey, err := readSecret(reader, "Enter Jira API Key")
+		if err != nil {
+			return nil, err
+		}
+		config.Api.Apikey = apikey
+
+		printPrompt("Enter Jira Email")
+		email, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		config.Api.Email = strings.TrimSpace(email)
+	}
+	config.Api.Agileurl = site + "/rest/agile/1.0"
 
-	fmt.Printf("%sEnter Jira Company Name %s(e.g., yourcompany → https://yourcompany.atlassian.net)%s:%s ", colorYellow, colorDim, colorYellow, colorReset)
-	companyName, err := reader.ReadString('\n')
+	tempClient, err := newJiraClient(config)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to create temporary jira client: %v", err)
 	}
-	companyName = strings.TrimSpace(companyName)
-	config.Api.Baseurl = fmt.Sprintf("https://%s.atlassian.net/rest/api/3", companyName)
-	config.Api.Agileurl = fmt.Sprintf("https://%s.atlassian.net/rest/agile/1.0", companyName)
 
-	printPrompt("Enter Jira Email")
-	email, err := reader.ReadString('\n')
+	config.Projects, err = selectProjectsAndBoards(tempClient, reader)
 	if err != nil {
 		return nil, err
 	}
-	config.Api.Email = strings.TrimSpace(email)
 
-	tempJiraCfg := jira.Config{
-		BaseURL:  config.Api.Baseurl,
-		AgileURL: config.Api.Agileurl,
-		Email:    config.Api.Email,
-		APIKey:   config.Api.Apikey,
+	fmt.Println()
+	printInfo("Detecting custom fields...")
+	if fields, err := tempClient.GetFields(context.Background()); err != nil {
+		printWarning("Failed to fetch fields: %v", err)
+	} else {
+		config.Fields = jira.DetectCommonFields(fields)
+		for name, id := range config.Fields {
+			printDim("  %s → %s", name, id)
+		}
 	}
 
-	tempClient, err := jira.NewClient(tempJiraCfg)
+	fmt.Println()
+	fmt.Printf("%sEnter Git Branch Base %s(e.g., main, master, develop)%s:%s ", colorYellow, colorDim, colorYellow, colorReset)
+	branchbase, err := reader.ReadString('\n')
 	if err != nil {
-		return nil, fmt.Errorf("failed to create temporary jira client: %v", err)
+		return nil, err
+	}
+	config.Git.Branchbase = strings.TrimSpace(branchbase)
+
+	if store, err := storeAPIToken(config, ""); err != nil {
+		printWarning("API key will be stored in plaintext: %v", err)
+	} else {
+		printDim("API key saved to %s", store.name())
 	}
 
+	return config, nil
+}
+
+// selectProjectsAndBoards lists the account's projects and their boards and asks which to use
+func selectProjectsAndBoards(client *jira.Client, reader *bufio.Reader) ([]Project, error) {
 	// Fetch available projects
 	fmt.Println()
 	printInfo("Fetching available projects...")
-	jiraProjects, err := tempClient.GetAllProjects(context.Background())
+	jiraProjects, err := client.GetAllProjects(context.Background())
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch projects: %v", err)
 	}
@@ -181,14 +372,14 @@ func promptForConfig() (*Config, error) {
 	}
 
 	// Fetch boards for selected projects
-	config.Projects = []Project{}
+	var projects []Project
 	for _, idx := range selectedProjectIndices {
 		jiraProject := jiraProjects[idx]
 
 		fmt.Println()
 		printInfo("Fetching boards for project %s...", jiraProject.Name)
 
-		jiraBoards, err := tempClient.GetProjectBoards(context.Background(), jiraProject.Key)
+		jiraBoards, err := client.GetProjectBoards(context.Background(), jiraProject.Key)
 		if err != nil {
 			printWarning("Failed to fetch boards for %s: %v", jiraProject.Name, err)
 			continue
@@ -236,7 +427,7 @@ func promptForConfig() (*Config, error) {
 		}
 
 		if len(selectedBoards) > 0 {
-			config.Projects = append(config.Projects, Project{
+			projects = append(projects, Project{
 				Name: jiraProject.Name,
 				ID: jiraProject.Key,
 				Boards: selectedBoards,
@@ -244,89 +435,303 @@ func promptForConfig() (*Config, error) {
 		}
 	}
 
-	if len(config.Projects) == 0 {
+	if len(projects) == 0 {
 		return nil, fmt.Errorf("no projects with boards were selected")
 	}
+	return projects, nil
+}
 
-	fmt.Println()
-	fmt.Printf("%sEnter Git Branch Base %s(e.g., main, master, develop)%s:%s ", colorYellow, colorDim, colorYellow, colorReset)
-	branchbase, err := reader.ReadString('\n')
-	if err != nil {
-		return nil, err
+// detectFlavor asks an instance's serverInfo, which needs no login, whether it is Cloud or
+// Server; *.atlassian.net is always Cloud and anything unreachable is assumed to be Server
+func detectFlavor(site string) jira.Flavor {
+	if strings.HasSuffix(site, ".atlassian.net") {
+		return jira.Cloud
 	}
-	config.Git.Branchbase = strings.TrimSpace(branchbase)
 
-	return config, nil
+	client, err := jira.NewClient(jira.Config{BaseURL: site + "/rest/api/2", AgileURL: site + "/rest/agile/1.0"})
+	if err != nil {
+		return jira.Server
+	}
+	flavor, err := client.Flavor(context.Background())
+	if err != nil {
+		return jira.Server
+	}
+	return flavor
 }
 
-func saveConfig(config *Config, filepath string) error {
-	dir := filepath[:strings.LastIndex(filepath, "/")]
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create config directory: %v", err)
+func saveConfig(config *Config, path string) error {
+	stored := *config
+	if config.file != nil {
+		stored = *config.file
 	}
 
-	file, err := os.Create(filepath)
-	if err != nil {
-		return fmt.Errorf("failed to create config file: %v", err)
+	// Never write back a token that was read from a credential store
+	stored.Api = withoutStoredToken(stored.Api)
+	profiles := map[string]*Profile{}
+	for name, profile := range stored.Profiles {
+		copied := *profile
+		copied.Api = withoutStoredToken(profile.Api)
+		profiles[name] = &copied
 	}
-	defer file.Close()
+	stored.Profiles = profiles
 
-	encoder := toml.NewEncoder(file)
-	if err := encoder.Encode(config); err != nil {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(&stored); err != nil {
 		return fmt.Errorf("failed to encode config: %v", err)
 	}
 
+	if err := writeFileAtomic(path, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write config file: %v", err)
+	}
 	return nil
 }
 
+// writeFileAtomic replaces a file through a temporary file in the same directory, so a
+// crash or full disk never leaves it half written. Missing directories are created private.
+// A symlinked file (e.g. a config.toml kept in a dotfiles repository) has its target
+// replaced rather than the link
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Chmod(perm); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+func withoutStoredToken(api APIConfig) APIConfig {
+	if api.CredentialStore != "" || api.CredentialCommand != "" {
+		api.Apikey = ""
+	}
+	return api
+}
+
 func getOrCreateConfig(filename string) (*Config, error) {
 	configPath := findConfig(filename)
 
 	if configPath != "" {
-		return loadConfig(configPath)
+		config, err := loadConfig(configPath)
+		if err != nil {
+			return nil, err
+		}
+		// A second, untouched copy to save changes into
+		if config.file, err = loadConfig(configPath); err != nil {
+			return nil, err
+		}
+		config.path = configPath
+		if err := checkConfigPermissions(configPath); err != nil {
+			// On stderr, so output meant for other programs (doctor -json, config show) stays clean
+			fmt.Fprintf(os.Stderr, "%s⚠ %v%s\n", colorYellow, err, colorReset)
+		}
+		if err := resolveConfig(config, configPath); err != nil {
+			return nil, err
+		}
+		return config, nil
+	}
+
+	// Everything needed may come from the environment, e.g. in CI
+	if hasAPIOverrides() {
+		config := &Config{}
+		if err := resolveConfig(config, ""); err != nil {
+			return nil, err
+		}
+		return config, nil
 	}
 
+	printWarning("No config file found.")
+	fmt.Println()
+
 	config, err := promptForConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get config from user: %v", err)
 	}
 
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %v", err)
+	if configPath, err = newConfigPath(filename); err != nil {
+		return nil, err
 	}
-
-	configPath = filepath.Join(homeDir, ".config", "jig", filename)
 	if err := saveConfig(config, configPath); err != nil {
 		return nil, err
 	}
+	config.path = configPath
 
 	fmt.Println()
 	printSuccess("Config file created at: %s", printHighlight(configPath))
 	fmt.Println()
+
+	if err := resolveConfig(config, configPath); err != nil {
+		return nil, err
+	}
 	return config, nil
 }
 
+// configDir returns the directory for config.toml: $XDG_CONFIG_HOME/jig or ~/.config/jig
+func configDir() (string, error) {
+	return xdgDir("XDG_CONFIG_HOME", ".config")
+}
+
+// stateDir returns the directory for jig's runtime state such as the running timer:
+// $XDG_STATE_HOME/jig or ~/.local/state/jig
+func stateDir() (string, error) {
+	return xdgDir("XDG_STATE_HOME", filepath.Join(".local", "state"))
+}
+
+// cacheDir returns the directory for data fetched from Jira that can be safely thrown away:
+// $XDG_CACHE_HOME/jig or ~/.cache/jig
+func cacheDir() (string, error) {
+	return xdgDir("XDG_CACHE_HOME", ".cache")
+}
+
+// xdgDir returns jig's directory under the base directory named by env, or under home when
+// env is unset; the XDG spec says relative paths are to be ignored
+func xdgDir(env, home string) (string, error) {
+	if base := os.Getenv(env); filepath.IsAbs(base) {
+		return filepath.Join(base, "jig"), nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %v", err)
+	}
+	return filepath.Join(homeDir, home, "jig"), nil
+}
+
+// findJigRC returns the .jigrc nearest to the current directory, see findJigRCs
 func findJigRC() string {
+	paths := findJigRCs()
+	if len(paths) == 0 {
+		return ""
+	}
+	return paths[len(paths)-1]
+}
+
+// findJigRCs returns the .jigrc files from the current directory up to the top of the git
+// repository, or the home directory outside of one, outermost first
+func findJigRCs() []string {
 	currentDir, err := os.Getwd()
 	if err != nil {
-		return ""
+		return nil
 	}
+	homeDir, _ := os.UserHomeDir()
 
+	var paths []string
 	for {
 		jigrcPath := filepath.Join(currentDir, ".jigrc")
 		if _, err := os.Stat(jigrcPath); err == nil {
-			return jigrcPath
+			paths = append([]string{jigrcPath}, paths...)
 		}
 
+		// .git is a directory, or a file in worktrees and submodules
+		_, err := os.Stat(filepath.Join(currentDir, ".git"))
 		parent := filepath.Dir(currentDir)
-		if parent == currentDir {
+		if err == nil || currentDir == homeDir || parent == currentDir {
 			break
 		}
 		currentDir = parent
 	}
 
-	return ""
+	return paths
+}
+
+// jigrcGroups are .jigrc keys that only make sense together: a .jigrc naming a board
+// replaces the board (and sprint) of the ones above it rather than mixing IDs and names
+var jigrcGroups = [][]string{
+	{"project_name", "project_id"},
+	{"board_name", "board_id", "sprint_name", "sprint_id"},
+	{"sprint_name", "sprint_id"},
+}
+
+// loadMergedJigRC merges the .jigrc files from findJigRCs, the nearest winning per key, and
+// returns which file each dotted key (e.g. git.branch_template) came from. It returns nil
+// when there is no .jigrc
+func loadMergedJigRC() (*JigRC, map[string]string, error) {
+	paths := findJigRCs()
+	if len(paths) == 0 {
+		return nil, nil, nil
+	}
+
+	merged := map[string]any{}
+	origins := map[string]string{}
+	for _, path := range paths {
+		// Checks the schema version of each file
+		if _, err := loadJigRC(path); err != nil {
+			return nil, nil, fmt.Errorf("failed to read %s: %v", path, err)
+		}
+		var raw map[string]any
+		if _, err := toml.DecodeFile(path, &raw); err != nil {
+			return nil, nil, fmt.Errorf("failed to read %s: %v", path, err)
+		}
+
+		for _, group := range jigrcGroups {
+			if slices.ContainsFunc(group, func(key string) bool { return !isZeroTOML(raw[key]) }) {
+				for _, key := range group {
+					delete(merged, key)
+					delete(origins, key)
+				}
+			}
+		}
+		mergeTOML(merged, raw, "", path, origins)
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(merged); err != nil {
+		return nil, nil, err
+	}
+	jigrc := &JigRC{}
+	if _, err := toml.Decode(buf.String(), jigrc); err != nil {
+		return nil, nil, err
+	}
+	return jigrc, origins, nil
+}
+
+// mergeTOML merges src into dst, merging tables key by key and replacing everything else.
+// Empty strings and zeros, which jig writes for unset values, don't replace anything
+func mergeTOML(dst, src map[string]any, prefix, path string, origins map[string]string) {
+	for key, value := range src {
+		if isZeroTOML(value) {
+			continue
+		}
+		if table, ok := value.(map[string]any); ok {
+			sub, ok := dst[key].(map[string]any)
+			if !ok {
+				sub = map[string]any{}
+				dst[key] = sub
+			}
+			mergeTOML(sub, table, prefix+key+".", path, origins)
+			continue
+		}
+		dst[key] = value
+		origins[prefix+key] = path
+	}
+}
+
+func isZeroTOML(value any) bool {
+	return value == nil || value == "" || value == int64(0)
 }
 
 func loadJigRC(filepath string) (*JigRC, error) {
@@ -353,6 +758,12 @@ func loadJigRC(filepath string) (*JigRC, error) {
 		return nil, err
 	}
 
+	if jigrc.Version > jigrcVersion {
+		return nil, fmt.Errorf("%s is .jigrc version %d, but this jig only knows version %d; upgrade jig",
+			filepath, jigrc.Version, jigrcVersion)
+	}
+	// Version 1 files need no changes; the new settings are all optional
+
 	return jigrc, nil
 }
 
@@ -362,17 +773,20 @@ func saveJigRC(jigrc *JigRC) error {
 		return err
 	}
 
-	filepath := filepath.Join(currentDir, ".jigrc")
-	file, err := os.Create(filepath)
-	if err != nil {
-		return fmt.Errorf("failed to create .jigrc file: %v", err)
-	}
-	defer file.Close()
+	return writeJigRC(jigrc, filepath.Join(currentDir, ".jigrc"))
+}
 
-	encoder := toml.NewEncoder(file)
-	if err := encoder.Encode(jigrc); err != nil {
+func writeJigRC(jigrc *JigRC, path string) error {
+	jigrc.Version = jigrcVersion
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(jigrc); err != nil {
 		return fmt.Errorf("failed to encode .jigrc: %v", err)
 	}
 
+	// .jigrc holds no secrets and is often committed, so it stays readable
+	if err := writeFileAtomic(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write .jigrc file: %v", err)
+	}
 	return nil
 }
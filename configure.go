@@ -0,0 +1,511 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/emilsto/jig/jira"
+)
+
+const configUsage = "usage: jig config get KEY | set KEY VALUE | edit | validate | show [--origin] | add-board | sources"
+
+// configKeys are the config.toml values jig config get and set work on, besides
+// fields.<name>, table.columns and profile
+var configKeys = []string{
+	"api.baseurl",
+	"api.agileurl",
+	"api.email",
+	"api.flavor",
+	"api.auth",
+	"api.credential_store",
+	"api.credential_command",
+	"api.oauth_client_id",
+	"api.cloud_id",
+	"git.branchbase",
+	"git.branch_template",
+	"git.commit_format",
+}
+
+// configKey returns the field a key of configKeys is stored in
+func configKey(api *APIConfig, git *GitConfig, key string) *string {
+	switch key {
+	case "api.baseurl":
+		return &api.Baseurl
+	case "api.agileurl":
+		return &api.Agileurl
+	case "api.email":
+		return &api.Email
+	case "api.flavor":
+		return &api.Flavor
+	case "api.auth":
+		return &api.Auth
+	case "api.credential_store":
+		return &api.CredentialStore
+	case "api.credential_command":
+		return &api.CredentialCommand
+	case "api.oauth_client_id":
+		return &api.OAuthClientID
+	case "api.cloud_id":
+		return &api.CloudID
+	case "git.branchbase":
+		return &git.Branchbase
+	case "git.branch_template":
+		return &git.BranchTemplate
+	case "git.commit_format":
+		return &git.CommitFormat
+	}
+	return nil
+}
+
+// checkConfigValue rejects values jig would fail on later, such as a misspelled auth
+func checkConfigValue(key, value string) error {
+	allowed := map[string][]string{
+		"api.flavor":           {"cloud", "server"},
+		"api.auth":             {"basic", "pat", "oauth"},
+		"api.credential_store": {"keyring", "pass"},
+	}
+	if values, ok := allowed[key]; ok && value != "" && !slices.Contains(values, value) {
+		return fmt.Errorf("%s must be one of %s, not %q", key, strings.Join(values, ", "), value)
+	}
+
+	if key == "api.baseurl" || key == "api.agileurl" {
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("%s must be a URL such as https://example.atlassian.net/rest/api/3, not %q", key, value)
+		}
+	}
+	return nil
+}
+
+// runConfigCommand implements `jig config ...`
+func runConfigCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf(configUsage)
+	}
+	if args[0] == "sources" && len(args) == 1 {
+		return runConfigSources()
+	}
+
+	config, err := getOrCreateConfig("config.toml")
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case args[0] == "get" && len(args) == 2:
+		return configGet(config, args[1])
+	case args[0] == "set" && len(args) >= 3:
+		return configSet(config, args[1], strings.Join(args[2:], " "))
+	case args[0] == "edit" && len(args) == 1:
+		return configEdit(config)
+	case args[0] == "validate" && len(args) == 1:
+		return configValidate(config)
+	case args[0] == "show":
+		fs := flag.NewFlagSet("config show", flag.ExitOnError)
+		origin := fs.Bool("origin", false, "Show which file, profile, variable or flag each value came from")
+		fs.Parse(args[1:])
+		return configShow(config, *origin)
+	case args[0] == "add-board" && len(args) == 1:
+		return configAddBoard(config)
+	default:
+		return fmt.Errorf(configUsage)
+	}
+}
+
+// configGet prints a resolved value, i.e. with the profile, .jigrc and overrides applied
+func configGet(config *Config, key string) error {
+	var value string
+	switch {
+	case key == "api.token" || key == "api.apikey":
+		return fmt.Errorf("the token isn't printed; see jig auth status")
+	case strings.HasPrefix(key, "fields."):
+		value = config.Fields[strings.TrimPrefix(key, "fields.")]
+	case key == "table.columns":
+		value = strings.Join(config.Table.Columns, ",")
+	case key == "profile" || key == "project" || key == "board":
+		value = *settingValue(config, key)
+	default:
+		field := configKey(&config.Api, &config.Git, key)
+		if field == nil {
+			return fmt.Errorf("unknown key %q; known keys: %s, fields.<name>, table.columns, profile, project, board", key, strings.Join(configKeys, ", "))
+		}
+		value = *field
+	}
+
+	fmt.Println(value)
+	return nil
+}
+
+// configSet changes a value in config.toml, in the active profile's section where it has one.
+// An empty value removes it
+func configSet(config *Config, key, value string) error {
+	if config.file == nil {
+		return fmt.Errorf("no config.toml to change")
+	}
+
+	switch {
+	case key == "api.token" || key == "api.apikey":
+		return fmt.Errorf("use jig auth login to change the token")
+	case key == "project" || key == "board":
+		return fmt.Errorf("the project and board are chosen per directory; run jig init")
+	case strings.HasPrefix(key, "fields."):
+		name := strings.TrimPrefix(key, "fields.")
+		if value == "" {
+			delete(storedFields(config), name)
+		} else {
+			storedFields(config)[name] = value
+		}
+	case key == "table.columns":
+		config.file.Table.Columns = nil
+		for _, column := range strings.Split(value, ",") {
+			if column = strings.TrimSpace(column); column != "" {
+				config.file.Table.Columns = append(config.file.Table.Columns, column)
+			}
+		}
+	case key == "profile":
+		if _, ok := config.Profiles[value]; value != "" && !ok {
+			return fmt.Errorf("unknown profile %q; see jig profile list", value)
+		}
+		config.file.Profile = value
+	default:
+		field := configKey(storedAPI(config), storedGit(config), key)
+		if field == nil {
+			return fmt.Errorf("unknown key %q; known keys: %s, fields.<name>, table.columns, profile", key, strings.Join(configKeys, ", "))
+		}
+		if err := checkConfigValue(key, value); value != "" && err != nil {
+			return err
+		}
+		*field = value
+	}
+
+	if err := saveConfig(config, config.path); err != nil {
+		return err
+	}
+	if config.activeProfile != "" && (strings.HasPrefix(key, "api.") || strings.HasPrefix(key, "git.") || strings.HasPrefix(key, "fields.")) {
+		printSuccess("Set %s in profile %s", key, config.activeProfile)
+	} else {
+		printSuccess("Set %s in %s", key, config.path)
+	}
+	return nil
+}
+
+// configProblems checks a config.toml for unknown keys and invalid values
+func configProblems(data string) []string {
+	var parsed Config
+	meta, err := toml.Decode(data, &parsed)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	var problems []string
+	for _, key := range meta.Undecoded() {
+		problems = append(problems, fmt.Sprintf("unknown key %s", key))
+	}
+
+	sections := map[string]*APIConfig{"api": &parsed.Api}
+	for name, profile := range parsed.Profiles {
+		sections["profiles."+name+".api"] = &profile.Api
+	}
+	for _, prefix := range slices.Sorted(maps.Keys(sections)) {
+		for _, key := range configKeys {
+			field := configKey(sections[prefix], &GitConfig{}, key)
+			if field == nil || *field == "" {
+				continue
+			}
+			if err := checkConfigValue(key, *field); err != nil {
+				problems = append(problems, strings.Replace(err.Error(), "api.", prefix+".", 1))
+			}
+		}
+	}
+
+	if parsed.Profile != "" && parsed.Profiles[parsed.Profile] == nil {
+		problems = append(problems, fmt.Sprintf("profile %q isn't defined under [profiles]", parsed.Profile))
+	}
+
+	projects := parsed.Projects
+	for _, profile := range parsed.Profiles {
+		projects = append(projects, profile.Projects...)
+	}
+	for _, project := range projects {
+		if project.ID == "" {
+			problems = append(problems, fmt.Sprintf("project %q has no ID (its key)", project.Name))
+		}
+		for _, board := range project.Boards {
+			if board.ID == 0 {
+				problems = append(problems, fmt.Sprintf("board %q of %s has no ID", board.Name, project.ID))
+			}
+		}
+	}
+	return problems
+}
+
+// configEdit opens config.toml in $EDITOR and only saves it once it is valid
+func configEdit(config *Config) error {
+	if config.path == "" {
+		return fmt.Errorf("no config.toml to edit")
+	}
+	data, err := os.ReadFile(config.path)
+	if err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	text := string(data)
+	for {
+		if text, err = editText(text, "config-*.toml"); err != nil {
+			return err
+		}
+
+		problems := configProblems(text)
+		if len(problems) == 0 {
+			break
+		}
+		for _, problem := range problems {
+			printError("%s", problem)
+		}
+		again, err := confirm(reader, "Edit again?", true)
+		if err != nil {
+			return err
+		}
+		if !again {
+			fmt.Println("Discarded changes")
+			return nil
+		}
+	}
+
+	if text == string(data) {
+		fmt.Println("No changes")
+		return nil
+	}
+	if err := writeFileAtomic(config.path, []byte(text), 0600); err != nil {
+		return err
+	}
+	printSuccess("Saved %s", printHighlight(config.path))
+	return nil
+}
+
+// configValidate checks config.toml and .jigrc, then the credentials, projects and boards against Jira
+func configValidate(config *Config) error {
+	failed := 0
+	fail := func(format string, args ...any) {
+		printError(format, args...)
+		failed++
+	}
+
+	fmt.Println()
+	printBold("Configuration:")
+	if config.path != "" {
+		data, err := os.ReadFile(config.path)
+		if err != nil {
+			return err
+		}
+		problems := configProblems(string(data))
+		for _, problem := range problems {
+			fail("%s: %s", config.path, problem)
+		}
+		if len(problems) == 0 {
+			printSuccess("%s is valid", config.path)
+		}
+	}
+	if config.project != "" || config.board != "" {
+		where := config.origins["board"]
+		if where == "" {
+			where = config.origins["project"]
+		}
+		if project, board := findProjectAndBoard(config, config.project, config.board); project == nil {
+			fail("project %q / board %q from %s aren't in config.toml", config.project, config.board, where)
+		} else {
+			printSuccess("project %s, board %s (from %s)", project.ID, board.Name, where)
+		}
+	}
+
+	fmt.Println()
+	printBold("Jira:")
+	client, err := newJiraClient(config)
+	if err != nil {
+		fail("%v", err)
+		return fmt.Errorf("%d problem(s) found", failed)
+	}
+	ctx := context.Background()
+	user, err := client.Myself(ctx)
+	if err != nil {
+		fail("%s: %v", config.Api.Baseurl, err)
+		return fmt.Errorf("%d problem(s) found", failed)
+	}
+	printSuccess("Authenticated as %s on %s", user.DisplayName, config.Api.Baseurl)
+
+	jiraProjects, err := client.GetAllProjects(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch projects: %v", err)
+	}
+	keys := map[string]string{}
+	for _, project := range jiraProjects {
+		keys[strings.ToUpper(project.Key)] = project.Name
+	}
+
+	for _, project := range config.Projects {
+		name, ok := keys[strings.ToUpper(project.ID)]
+		if !ok {
+			fail("project %s doesn't exist or isn't visible to you", project.ID)
+			continue
+		}
+		printSuccess("project %s (%s)", project.ID, name)
+
+		jiraBoards, err := client.GetProjectBoards(ctx, project.ID)
+		if err != nil {
+			fail("  boards of %s: %v", project.ID, err)
+			continue
+		}
+		for _, board := range project.Boards {
+			if slices.ContainsFunc(jiraBoards, func(b jira.JiraBoard) bool { return b.ID == board.ID }) {
+				printSuccess("  board %d (%s)", board.ID, board.Name)
+			} else if _, err := client.GetBoardConfiguration(ctx, board.ID); err != nil {
+				fail("  board %d (%s) doesn't exist or isn't visible to you", board.ID, board.Name)
+			} else {
+				fail("  board %d (%s) isn't a board of %s", board.ID, board.Name, project.ID)
+			}
+		}
+	}
+
+	fmt.Println()
+	if failed > 0 {
+		return fmt.Errorf("%d problem(s) found", failed)
+	}
+	printSuccess("Everything checks out")
+	return nil
+}
+
+// configShow prints the configuration in effect, after profiles, .jigrc and overrides; with
+// origin every value is annotated with where it came from
+func configShow(config *Config, origin bool) error {
+	shown := *config
+	if shown.Api.Apikey != "" {
+		shown.Api.Apikey = "********"
+	}
+	shown.Profiles = nil
+
+	if origin && len(config.jigrcs) > 0 {
+		printDim("# .jigrc files, nearest last: %s", strings.Join(config.jigrcs, ", "))
+	}
+	if config.activeProfile != "" {
+		printDim("# profile %s%s", config.activeProfile, originNote(origin, config.origins["profile"]))
+	}
+	if config.project != "" || config.board != "" {
+		printDim("# project %s, board %s%s", config.project, config.board, originNote(origin, config.origins["board"]))
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(shown); err != nil {
+		return err
+	}
+	if !origin {
+		fmt.Print(buf.String())
+		return nil
+	}
+
+	table := ""
+	for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			table = strings.Trim(trimmed, "[]")
+			fmt.Println(line)
+			continue
+		}
+		key, _, ok := strings.Cut(trimmed, " = ")
+		if !ok {
+			fmt.Println(line)
+			continue
+		}
+		if table != "" {
+			key = table + "." + key
+		}
+		fmt.Printf("%-60s %s# %s%s\n", line, colorDim, valueOrigin(config, key), colorReset)
+	}
+	return nil
+}
+
+func originNote(origin bool, from string) string {
+	if !origin || from == "" {
+		return ""
+	}
+	return " (from " + from + ")"
+}
+
+// valueOrigin returns where a value of the effective config came from: a flag, variable or
+// .jigrc recorded by resolveConfig, the active profile, or else config.toml
+func valueOrigin(config *Config, key string) string {
+	if key == "api.apikey" {
+		key = "api.token"
+	}
+	if origin := config.origins[key]; origin != "" {
+		return origin
+	}
+
+	if profile := config.Profiles[config.activeProfile]; profile != nil {
+		section, _, _ := strings.Cut(key, ".")
+		switch {
+		case section == "api",
+			section == "git" && profile.Git != (GitConfig{}),
+			section == "projects" && len(profile.Projects) > 0,
+			section == "fields" && profile.Fields != nil:
+			return "profile " + config.activeProfile
+		}
+	}
+	if config.path != "" {
+		return config.path
+	}
+	return "default"
+}
+
+// configAddBoard adds projects and boards to config.toml using the first-run selection
+func configAddBoard(config *Config) error {
+	if config.file == nil {
+		return fmt.Errorf("no config.toml to add boards to")
+	}
+
+	client, err := newJiraClient(config)
+	if err != nil {
+		return err
+	}
+	added, err := selectProjectsAndBoards(client, bufio.NewReader(os.Stdin))
+	if err != nil {
+		return err
+	}
+
+	projects := storedProjects(config)
+	count := 0
+	for _, project := range added {
+		i := slices.IndexFunc(*projects, func(p Project) bool { return strings.EqualFold(p.ID, project.ID) })
+		if i < 0 {
+			*projects = append(*projects, project)
+			count += len(project.Boards)
+			continue
+		}
+		for _, board := range project.Boards {
+			if !slices.ContainsFunc((*projects)[i].Boards, func(b Board) bool { return b.ID == board.ID }) {
+				(*projects)[i].Boards = append((*projects)[i].Boards, board)
+				count++
+			}
+		}
+	}
+
+	if count == 0 {
+		fmt.Println("Those boards are already configured")
+		return nil
+	}
+	if err := saveConfig(config, config.path); err != nil {
+		return err
+	}
+	fmt.Println()
+	printSuccess("Added %d board(s) to %s", count, printHighlight(config.path))
+	return nil
+}
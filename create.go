@@ -0,0 +1,241 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/emilsto/jig/jira"
+)
+
+// Fields that the create flow fills in itself rather than prompting from createmeta
+var handledCreateFields = map[string]bool{
+	"project":     true,
+	"issuetype":   true,
+	"summary":     true,
+	"description": true,
+	"reporter":    true,
+}
+
+type createOptions struct {
+	issueType       string
+	summary         string
+	descriptionFile string
+	editDescription bool
+	addToSprint     bool
+	assignToMe      bool
+	// askFollowUps prompts for sprint and assignment instead of relying on the flags above
+	askFollowUps bool
+}
+
+// createIssueFlow walks the user through creating an issue in projectKey and returns the new key
+func createIssueFlow(client *jira.Client, reader *bufio.Reader, projectKey string, sprint *jira.Sprint, opts createOptions) (string, error) {
+	ctx := context.Background()
+
+	printInfo("Fetching issue types for %s...", projectKey)
+	allTypes, err := client.GetProjectIssueTypes(ctx, projectKey)
+	if err != nil {
+		return "", err
+	}
+
+	var issueTypes []jira.IssueType
+	for _, it := range allTypes {
+		if !it.Subtask {
+			issueTypes = append(issueTypes, it)
+		}
+	}
+	if len(issueTypes) == 0 {
+		return "", fmt.Errorf("no creatable issue types in project %s", projectKey)
+	}
+
+	issueType, err := selectIssueType(reader, issueTypes, opts.issueType)
+	if err != nil {
+		return "", err
+	}
+
+	summary := strings.TrimSpace(opts.summary)
+	if summary == "" {
+		printPrompt(fmt.Sprintf("Enter %s summary", issueType.Name))
+		summary, err = reader.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		summary = strings.TrimSpace(summary)
+	}
+	if summary == "" {
+		return "", fmt.Errorf("summary cannot be empty")
+	}
+
+	var description string
+	switch {
+	case opts.descriptionFile != "":
+		description, err = readMarkdown(opts.descriptionFile, "")
+	case opts.editDescription:
+		description, err = readMarkdown("", "")
+	default:
+		printPrompt("Enter description (optional, 'e' to open $EDITOR)")
+		description, err = reader.ReadString('\n')
+		if err == nil && strings.TrimSpace(description) == "e" {
+			description, err = readMarkdown("", "")
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	description = strings.TrimSpace(description)
+
+	fields := map[string]any{
+		"project":   map[string]any{"key": projectKey},
+		"issuetype": map[string]any{"id": issueType.ID},
+		"summary":   summary,
+	}
+	if description != "" {
+		fields["description"] = jira.MarkdownToADF(description)
+	}
+
+	metaFields, err := client.GetCreateFields(ctx, projectKey, issueType.ID)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch create metadata: %v", err)
+	}
+
+	for _, field := range metaFields {
+		if !field.Required || field.HasDefaultValue || handledCreateFields[fieldID(field)] {
+			continue
+		}
+		value, err := promptFieldValue(reader, field)
+		if err != nil {
+			return "", err
+		}
+		if value != nil {
+			fields[fieldID(field)] = value
+		}
+	}
+
+	addToSprint := opts.addToSprint
+	assignToMe := opts.assignToMe
+	if opts.askFollowUps {
+		if sprint != nil {
+			addToSprint, err = confirm(reader, fmt.Sprintf("Add to sprint %s?", sprint.Name), true)
+			if err != nil {
+				return "", err
+			}
+		}
+		assignToMe, err = confirm(reader, "Assign to yourself?", false)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	fmt.Println()
+	printInfo("Creating %s in %s...", issueType.Name, projectKey)
+	key, err := client.CreateIssue(ctx, fields)
+	if err != nil {
+		return "", err
+	}
+	printSuccess("%s created: %s", issueType.Name, printHighlight(key))
+
+	if addToSprint {
+		if sprint == nil {
+			printWarning("No active sprint to add %s to", key)
+		} else if err := client.MoveIssuesToSprint(ctx, sprint.ID, []string{key}); err != nil {
+			printWarning("Failed to add %s to sprint %s: %v", key, sprint.Name, err)
+		} else {
+			printSuccess("Added %s to sprint %s", printHighlight(key), sprint.Name)
+		}
+	}
+
+	if assignToMe {
+		if err := client.AssignToSelf(ctx, key); err != nil {
+			printWarning("Failed to assign %s: %v", key, err)
+		} else {
+			printSuccess("Assigned %s to self", printHighlight(key))
+		}
+	}
+
+	return key, nil
+}
+
+// selectIssueType matches name against the available types, or prompts when name is empty
+func selectIssueType(reader *bufio.Reader, issueTypes []jira.IssueType, name string) (jira.IssueType, error) {
+	if name != "" {
+		for _, it := range issueTypes {
+			if strings.EqualFold(it.Name, name) {
+				return it, nil
+			}
+		}
+		return jira.IssueType{}, fmt.Errorf("unknown issue type %q", name)
+	}
+
+	fmt.Println()
+	printBold("Issue Types:")
+	for i, it := range issueTypes {
+		fmt.Printf("  %d. %s\n", i+1, printHighlight(it.Name))
+	}
+
+	fmt.Println()
+	printPrompt("Select issue type number")
+	input, err := reader.ReadString('\n')
+	if err != nil {
+		return jira.IssueType{}, err
+	}
+
+	selection, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil || selection < 1 || selection > len(issueTypes) {
+		return jira.IssueType{}, fmt.Errorf("invalid selection")
+	}
+
+	return issueTypes[selection-1], nil
+}
+
+// runCreateCommand implements `jig create`
+func runCreateCommand(args []string) error {
+	fs := flag.NewFlagSet("create", flag.ExitOnError)
+	issueType := fs.String("type", "", "Issue type (Story, Task, Bug, Epic, ...)")
+	summary := fs.String("summary", "", "Issue summary")
+	descFile := fs.String("f", "", "Read description Markdown from file ('-' for stdin)")
+	editDesc := fs.Bool("e", false, "Write the description in $EDITOR")
+	addToSprint := fs.Bool("sprint", false, "Add the new issue to the current sprint")
+	assignToMe := fs.Bool("me", false, "Assign the new issue to yourself")
+	fs.Parse(args)
+
+	config, client, err := loadClient()
+	if err != nil {
+		return err
+	}
+
+	project, board, err := selectProjectAndBoard(config)
+	if err != nil {
+		return fmt.Errorf("failed to select project/board: %v", err)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	var sprint *jira.Sprint
+	if *addToSprint {
+		sprint, err = selectSprint(client, board, reader, "")
+		if err != nil {
+			return err
+		}
+	}
+
+	opts := createOptions{
+		issueType:       *issueType,
+		summary:         *summary,
+		descriptionFile: *descFile,
+		editDescription: *editDesc,
+		addToSprint:     *addToSprint,
+		assignToMe:      *assignToMe,
+	}
+
+	_, err = createIssueFlow(client, reader, project.ID, sprint, opts)
+	return err
+}
+
+func handleCreateIssue(ctx *actionContext) error {
+	sprint := ctx.sprint
+	_, err := createIssueFlow(ctx.jiraClient, ctx.reader, ctx.project.ID, &sprint, createOptions{askFollowUps: true})
+	return err
+}
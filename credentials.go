@@ -0,0 +1,400 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// credentialStore keeps the Jira API token outside config.toml
+type credentialStore interface {
+	name() string
+	get(account string) (string, error)
+	set(account, token string) error
+	delete(account string) error
+}
+
+// keyringStore uses the OS keyring: the macOS keychain via security(1), or the Secret
+// Service (GNOME Keyring, KWallet) via libsecret's secret-tool on Linux
+type keyringStore struct{}
+
+func (keyringStore) name() string { return "keyring" }
+
+func (keyringStore) get(account string) (string, error) {
+	if runtime.GOOS == "darwin" {
+		return runSecretCommand(nil, "security", "find-generic-password", "-s", "jig", "-a", account, "-w")
+	}
+	return runSecretCommand(nil, "secret-tool", "lookup", "service", "jig", "account", account)
+}
+
+func (keyringStore) set(account, token string) error {
+	if runtime.GOOS == "darwin" {
+		// security -i reads the command from stdin, which keeps the token out of argv where
+		// other users could see it in ps
+		command := fmt.Sprintf("add-generic-password -U -s jig -a %s -w %s\n", securityQuote(account), securityQuote(token))
+		if _, err := runSecretCommand(strings.NewReader(command), "security", "-i"); err != nil {
+			return err
+		}
+		// security -i exits successfully even when the command fails, so read the token back
+		if stored, err := (keyringStore{}).get(account); err != nil || stored != token {
+			return fmt.Errorf("security: failed to store the token in the keychain")
+		}
+		return nil
+	}
+	_, err := runSecretCommand(strings.NewReader(token), "secret-tool", "store", "--label", "jig: "+account, "service", "jig", "account", account)
+	return err
+}
+
+func (keyringStore) delete(account string) error {
+	if runtime.GOOS == "darwin" {
+		_, err := runSecretCommand(nil, "security", "delete-generic-password", "-s", "jig", "-a", account)
+		return err
+	}
+	_, err := runSecretCommand(nil, "secret-tool", "clear", "service", "jig", "account", account)
+	return err
+}
+
+// securityQuote quotes an argument for a command read by security -i
+func securityQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// passStore uses pass, the standard unix password manager, under jig/<account>
+type passStore struct{}
+
+func (passStore) name() string { return "pass" }
+
+func (passStore) get(account string) (string, error) {
+	out, err := runSecretCommand(nil, "pass", "show", "jig/"+account)
+	// pass entries may carry extra lines after the password
+	first, _, _ := strings.Cut(out, "\n")
+	return first, err
+}
+
+func (passStore) set(account, token string) error {
+	_, err := runSecretCommand(strings.NewReader(token+"\n"), "pass", "insert", "-m", "-f", "jig/"+account)
+	return err
+}
+
+func (passStore) delete(account string) error {
+	_, err := runSecretCommand(nil, "pass", "rm", "-f", "jig/"+account)
+	return err
+}
+
+// commandStore runs a user supplied command whose stdout is the token, e.g.
+// "op read op://work/jira/token"; it can only be read from
+type commandStore struct {
+	command string
+}
+
+func (commandStore) name() string { return "credential_command" }
+
+func (s commandStore) get(string) (string, error) {
+	return runSecretCommand(nil, "sh", "-c", s.command)
+}
+
+func (commandStore) set(string, string) error {
+	return fmt.Errorf("credential_command is read-only; store the token with that tool instead")
+}
+
+func (commandStore) delete(string) error {
+	return fmt.Errorf("credential_command is read-only; remove the token with that tool instead")
+}
+
+func runSecretCommand(stdin *strings.Reader, name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s: %v: %s", name, err, msg)
+		}
+		return "", fmt.Errorf("%s: %v", name, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// newCredentialStore returns the store named in config.toml, or nil when the token is kept there in plaintext
+func newCredentialStore(config *Config) (credentialStore, error) {
+	if config.Api.CredentialCommand != "" {
+		return commandStore{command: config.Api.CredentialCommand}, nil
+	}
+	switch config.Api.CredentialStore {
+	case "":
+		return nil, nil
+	case "keyring":
+		return keyringStore{}, nil
+	case "pass":
+		return passStore{}, nil
+	default:
+		return nil, fmt.Errorf("unknown credential_store %q, expected keyring or pass", config.Api.CredentialStore)
+	}
+}
+
+// defaultCredentialStore picks the OS keyring when its tool is installed, then pass
+func defaultCredentialStore() (credentialStore, error) {
+	if runtime.GOOS == "darwin" {
+		return keyringStore{}, nil
+	}
+	if _, err := exec.LookPath("secret-tool"); err == nil {
+		return keyringStore{}, nil
+	}
+	if _, err := exec.LookPath("pass"); err == nil {
+		return passStore{}, nil
+	}
+	return nil, fmt.Errorf("no credential store found; install secret-tool (libsecret) or pass, or set credential_command")
+}
+
+// credentialAccount identifies the token in a store, e.g. "me@example.com@example.atlassian.net",
+// or just the host for a personal access token
+func credentialAccount(config *Config) string {
+	host := config.Api.Baseurl
+	if u, err := url.Parse(config.Api.Baseurl); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	if config.Api.Email == "" {
+		return host
+	}
+	return config.Api.Email + "@" + host
+}
+
+// resolveAPIToken fills in Api.Apikey from the configured credential store
+func resolveAPIToken(config *Config) error {
+	store, err := newCredentialStore(config)
+	if err != nil || store == nil {
+		return err
+	}
+
+	token, err := store.get(credentialAccount(config))
+	if err != nil {
+		return fmt.Errorf("failed to read API token from %s: %v; run jig auth login", store.name(), err)
+	}
+	if token == "" {
+		return fmt.Errorf("no API token in %s; run jig auth login", store.name())
+	}
+	config.Api.Apikey = token
+	return nil
+}
+
+// storeAPIToken saves config.Api.Apikey in a credential store and records the store in config
+func storeAPIToken(config *Config, storeName string) (credentialStore, error) {
+	var store credentialStore
+	var err error
+	switch storeName {
+	case "":
+		store, err = defaultCredentialStore()
+	case "keyring":
+		store = keyringStore{}
+	case "pass":
+		store = passStore{}
+	default:
+		err = fmt.Errorf("unknown store %q, expected keyring or pass", storeName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := store.set(credentialAccount(config), config.Api.Apikey); err != nil {
+		return nil, fmt.Errorf("failed to save API token to %s: %v", store.name(), err)
+	}
+	config.Api.CredentialStore = store.name()
+	return store, nil
+}
+
+// readSecret reads a line without echoing it when stdin is a terminal
+func readSecret(reader *bufio.Reader, prompt string) (string, error) {
+	printPrompt(prompt)
+
+	echoOff := exec.Command("stty", "-echo")
+	echoOff.Stdin = os.Stdin
+	if echoOff.Run() == nil {
+		defer func() {
+			echoOn := exec.Command("stty", "echo")
+			echoOn.Stdin = os.Stdin
+			echoOn.Run()
+			fmt.Println()
+		}()
+	}
+
+	input, err := reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(input), nil
+}
+
+// runAuthCommand implements `jig auth login|logout|status`
+func runAuthCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: jig auth login [-store keyring|pass] [-oauth [-client-id ID] [-port N]] | logout | status")
+	}
+
+	if findConfig("config.toml") == "" {
+		return fmt.Errorf("no config.toml found; run jig once to create one")
+	}
+	config, err := getOrCreateConfig("config.toml")
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "login":
+		return authLogin(config, args[1:])
+	case "logout":
+		return authLogout(config)
+	case "status":
+		return authStatus(config)
+	default:
+		return fmt.Errorf("unknown auth command %q", args[0])
+	}
+}
+
+// authLogin stores a token in a credential store. Without a new token it migrates the
+// plaintext apikey from config.toml. With a profile active, it logs in to that profile
+func authLogin(config *Config, args []string) error {
+	fs := flag.NewFlagSet("auth login", flag.ExitOnError)
+	storeName := fs.String("store", "", "Where to keep the token: keyring or pass (default: whichever is available)")
+	oauth := fs.Bool("oauth", false, "Log in with OAuth 2.0 in the browser instead of an API token")
+	clientID := fs.String("client-id", os.Getenv("JIG_OAUTH_CLIENT_ID"), "OAuth app client ID (with -oauth)")
+	port := fs.Int("port", 8085, "Port of the localhost OAuth callback (with -oauth)")
+	fs.Parse(args)
+
+	if *oauth {
+		return authLoginOAuth(config, *storeName, *clientID, *port)
+	}
+
+	if config.Api.CredentialCommand != "" {
+		return fmt.Errorf("the token comes from credential_command; remove it from %s to use a store", config.path)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	stored := storedAPI(config)
+	if stored.Apikey != "" {
+		printInfo("Moving the API token out of %s", config.path)
+		config.Api.Apikey = stored.Apikey
+	} else {
+		prompt := fmt.Sprintf("API token for %s", config.Api.Email)
+		if config.Api.Email == "" || config.Api.Auth == "pat" {
+			printDim("Create a personal access token in Jira under Profile → Personal Access Tokens")
+			prompt = "Personal access token for " + credentialAccount(config)
+		} else {
+			printDim("Create a token at https://id.atlassian.com/manage-profile/security/api-tokens")
+		}
+		token, err := readSecret(reader, prompt)
+		if err != nil {
+			return err
+		}
+		if token == "" {
+			return fmt.Errorf("no token given")
+		}
+		config.Api.Apikey = token
+	}
+	config.tokenErr = nil
+
+	client, err := newJiraClient(config)
+	if err != nil {
+		return err
+	}
+	if err := client.VerifyCredentials(context.Background()); err != nil {
+		return fmt.Errorf("token rejected by %s: %v", config.Api.Baseurl, err)
+	}
+
+	store, err := storeAPIToken(config, *storeName)
+	if err != nil {
+		return err
+	}
+	stored.CredentialStore = store.name()
+	if stored.Auth == "oauth" {
+		stored.Auth = ""
+	}
+	if err := saveConfig(config, config.path); err != nil {
+		return err
+	}
+
+	printSuccess("API token saved to %s", store.name())
+	return nil
+}
+
+func authLogout(config *Config) error {
+	store, err := newCredentialStore(config)
+	if err != nil {
+		return err
+	}
+	stored := storedAPI(config)
+	if store == nil {
+		if stored.Apikey == "" {
+			return fmt.Errorf("not logged in")
+		}
+		stored.Apikey = ""
+		if err := saveConfig(config, config.path); err != nil {
+			return err
+		}
+		printSuccess("Removed the API token from %s", config.path)
+		return nil
+	}
+
+	if err := store.delete(credentialAccount(config)); err != nil {
+		return err
+	}
+	stored.CredentialStore = ""
+	if err := saveConfig(config, config.path); err != nil {
+		return err
+	}
+	printSuccess("Removed the API token from %s", store.name())
+	return nil
+}
+
+func authStatus(config *Config) error {
+	if config.activeProfile != "" {
+		fmt.Printf("  %sProfile:%s  %s\n", colorDim, colorReset, config.activeProfile)
+	}
+	fmt.Printf("  %sAccount:%s  %s\n", colorDim, colorReset, config.Api.Email)
+	fmt.Printf("  %sJira:%s     %s\n", colorDim, colorReset, config.Api.Baseurl)
+
+	store, err := newCredentialStore(config)
+	if err != nil {
+		return err
+	}
+	source := "plaintext in " + config.path
+	if store != nil {
+		source = store.name()
+	}
+	fmt.Printf("  %sToken:%s    %s\n", colorDim, colorReset, source)
+	if config.Api.Auth == "oauth" {
+		fmt.Printf("  %sOAuth:%s    client %s, cloud ID %s\n", colorDim, colorReset, config.Api.OAuthClientID, config.Api.CloudID)
+	}
+
+	if config.tokenErr != nil {
+		return config.tokenErr
+	}
+	if config.Api.Apikey == "" {
+		return fmt.Errorf("no API token; run jig auth login")
+	}
+
+	client, err := newJiraClient(config)
+	if err != nil {
+		return err
+	}
+	if err := client.VerifyCredentials(context.Background()); err != nil {
+		printError("Token rejected: %v", err)
+		return nil
+	}
+	printSuccess("Authenticated")
+
+	if store == nil {
+		printWarning("The token is stored in plaintext; run jig auth login to move it to the keyring or pass")
+	}
+	return nil
+}
@@ -0,0 +1,370 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/emilsto/jig/jira"
+)
+
+// doctorCheck is one line of the jig doctor report
+type doctorCheck struct {
+	Section string `json:"section"`
+	Name    string `json:"name"`
+	// Status is pass, warn or fail
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// doctor collects checks, printing them as they come unless the report is JSON
+type doctor struct {
+	json    bool
+	section string
+	checks  []doctorCheck
+}
+
+func (d *doctor) begin(section, title string) {
+	d.section = section
+	if !d.json {
+		fmt.Println()
+		printBold("%s:", title)
+	}
+}
+
+func (d *doctor) add(status, name, format string, args ...any) {
+	check := doctorCheck{Section: d.section, Name: name, Status: status, Detail: fmt.Sprintf(format, args...)}
+	d.checks = append(d.checks, check)
+	if d.json {
+		return
+	}
+
+	line := check.Name
+	if check.Detail != "" {
+		line += ": " + check.Detail
+	}
+	switch status {
+	case "pass":
+		printSuccess("%s", line)
+	case "warn":
+		printWarning("%s", line)
+	default:
+		printError("%s", line)
+	}
+}
+
+func (d *doctor) pass(name, format string, args ...any) { d.add("pass", name, format, args...) }
+func (d *doctor) warn(name, format string, args ...any) { d.add("warn", name, format, args...) }
+func (d *doctor) fail(name, format string, args ...any) { d.add("fail", name, format, args...) }
+
+// runDoctorCommand implements `jig doctor`, checking the configuration, Jira, git and the
+// terminal so problems can be told apart (and pasted into bug reports with -json)
+func runDoctorCommand(args []string) error {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "Print the report as JSON")
+	fs.Parse(args)
+
+	d := &doctor{json: *asJSON}
+	config := d.checkConfig()
+	if config != nil {
+		d.checkJira(config)
+	}
+	d.checkGit(config)
+	d.checkTerminal()
+
+	failed := 0
+	for _, check := range d.checks {
+		if check.Status == "fail" {
+			failed++
+		}
+	}
+
+	if d.json {
+		report := struct {
+			OS     string        `json:"os"`
+			Arch   string        `json:"arch"`
+			Go     string        `json:"go"`
+			Checks []doctorCheck `json:"checks"`
+		}{runtime.GOOS, runtime.GOARCH, runtime.Version(), d.checks}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		fmt.Println()
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	if !d.json {
+		printSuccess("No problems found")
+	}
+	return nil
+}
+
+// checkConfig reports the config.toml and .jigrc in use and returns the resolved config,
+// or nil if there is none to check Jira with. It never prompts for a new config
+func (d *doctor) checkConfig() *Config {
+	d.begin("config", "Configuration")
+
+	path := findConfig("config.toml")
+	switch {
+	case path != "":
+		d.pass("config.toml", "%s", path)
+		if err := checkConfigPermissions(path); err != nil {
+			d.warn("config.toml", "%v", err)
+		}
+		if data, err := os.ReadFile(path); err != nil {
+			d.fail("config.toml", "%v", err)
+		} else {
+			for _, problem := range configProblems(string(data)) {
+				d.fail("config.toml", "%s", problem)
+			}
+		}
+	case hasAPIOverrides():
+		d.pass("config.toml", "not found; using settings from flags and the environment")
+	default:
+		d.fail("config.toml", "not found; run jig to create one")
+	}
+
+	jigrcPaths := findJigRCs()
+	if len(jigrcPaths) == 0 {
+		d.pass(".jigrc", "not found; project and board are chosen when jig starts")
+	}
+	for _, jigrcPath := range jigrcPaths {
+		if _, err := loadJigRC(jigrcPath); err != nil {
+			d.fail(".jigrc", "%s: %v", jigrcPath, err)
+		} else {
+			d.pass(".jigrc", "%s", jigrcPath)
+		}
+	}
+
+	if path == "" && !hasAPIOverrides() {
+		return nil
+	}
+	config, err := getOrCreateConfig("config.toml")
+	if err != nil {
+		d.fail("settings", "%v", err)
+		return nil
+	}
+
+	if config.activeProfile != "" {
+		d.pass("profile", "%s (from %s)", config.activeProfile, config.origins["profile"])
+	}
+	if config.project != "" || config.board != "" {
+		if project, board := findProjectAndBoard(config, config.project, config.board); project == nil {
+			d.fail("project", "project %q / board %q aren't in config.toml", config.project, config.board)
+		} else {
+			d.pass("project", "%s, board %s (%d)", project.ID, board.Name, board.ID)
+		}
+	}
+
+	switch {
+	case config.tokenErr != nil:
+		d.fail("token", "%v", config.tokenErr)
+	case config.Api.Apikey == "":
+		d.warn("token", "none; requests are anonymous (run jig auth login)")
+	case isOverride(config.origins["api.token"]):
+		d.pass("token", "from %s", config.origins["api.token"])
+	case config.Api.CredentialStore != "":
+		d.pass("token", "from %s", config.Api.CredentialStore)
+	case config.Api.CredentialCommand != "":
+		d.pass("token", "from credential_command")
+	default:
+		d.warn("token", "stored in plaintext in %s (run jig auth login to move it)", config.path)
+	}
+	return config
+}
+
+// checkJira reports whether the API and agile URLs respond, who jig is authenticated as
+// and what it may do in the configured projects
+func (d *doctor) checkJira(config *Config) {
+	d.begin("jira", "Jira")
+
+	baseURL, agileURL := config.Api.Baseurl, config.Api.Agileurl
+	if config.Api.Auth == "oauth" {
+		baseURL, agileURL = jira.CloudAPIURLs(config.Api.CloudID)
+	}
+	d.checkURL("api", baseURL, "serverInfo")
+	d.checkURL("agile", agileURL, "board?maxResults=1")
+
+	client, err := newJiraClient(config)
+	if err != nil {
+		d.fail("client", "%v", err)
+		return
+	}
+
+	ctx := context.Background()
+	if info, err := client.GetServerInfo(ctx); err == nil {
+		d.pass("version", "%s %s", info.DeploymentType, info.Version)
+	}
+
+	user, err := client.Myself(ctx)
+	if err != nil {
+		d.fail("user", "%v", err)
+		return
+	}
+	name := user.DisplayName
+	if user.EmailAddress != "" {
+		name += " <" + user.EmailAddress + ">"
+	}
+	d.pass("user", "%s", name)
+
+	d.begin("permissions", "Permissions")
+	if len(config.Projects) == 0 {
+		d.warn("projects", "none configured (run jig config add-board)")
+	}
+	permissions := []string{jira.PermissionBrowseProjects, jira.PermissionTransitionIssues, jira.PermissionAssignIssues}
+	for _, project := range config.Projects {
+		granted, err := client.GetMyPermissions(ctx, project.ID, permissions...)
+		if err != nil {
+			d.fail(project.ID, "%v", err)
+			continue
+		}
+		var missing []string
+		for _, permission := range permissions {
+			if !granted[permission] {
+				missing = append(missing, strings.ToLower(strings.ReplaceAll(permission, "_", " ")))
+			}
+		}
+		if len(missing) > 0 {
+			d.fail(project.ID, "missing %s", strings.Join(missing, ", "))
+		} else {
+			d.pass(project.ID, "browse, transition and assign issues")
+		}
+	}
+}
+
+// checkURL reports whether a Jira URL answers at all, without credentials, so network
+// problems aren't mistaken for authentication ones
+func (d *doctor) checkURL(name, base, path string) {
+	if base == "" {
+		d.fail(name, "no URL configured")
+		return
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	start := time.Now()
+	resp, err := client.Get(strings.TrimSuffix(base, "/") + "/" + path)
+	if err != nil {
+		d.fail(name, "%s: %v", base, err)
+		return
+	}
+	resp.Body.Close()
+
+	elapsed := time.Since(start).Round(time.Millisecond)
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		d.fail(name, "%s: not found (%s); check the URL", base, resp.Status)
+	case resp.StatusCode >= 500:
+		d.fail(name, "%s: %s", base, resp.Status)
+	default:
+		// Without credentials a 401 or 403 still means the URL is right
+		d.pass(name, "%s responds in %s", base, elapsed)
+	}
+}
+
+// checkGit reports whether git is installed and the state of the current repository
+func (d *doctor) checkGit(config *Config) {
+	d.begin("git", "Git")
+
+	if _, err := exec.LookPath("git"); err != nil {
+		d.fail("git", "not found in PATH; branches can't be created")
+		return
+	}
+	version, err := gitOutput("--version")
+	if err != nil {
+		d.fail("git", "%v", err)
+		return
+	}
+	d.pass("git", "%s", strings.TrimPrefix(version, "git version "))
+
+	toplevel, err := gitOutput("rev-parse", "--show-toplevel")
+	if err != nil {
+		d.warn("repository", "not in a git repository")
+		return
+	}
+	d.pass("repository", "%s", toplevel)
+
+	if branch, err := gitOutput("symbolic-ref", "--short", "HEAD"); err != nil {
+		d.warn("branch", "detached HEAD")
+	} else {
+		d.pass("branch", "%s", branch)
+	}
+
+	if status, err := gitOutput("status", "--porcelain"); err != nil {
+		d.fail("status", "%v", err)
+	} else if status != "" {
+		d.warn("status", "%d uncommitted change(s); they move to new branches", len(strings.Split(status, "\n")))
+	} else {
+		d.pass("status", "clean")
+	}
+
+	if config == nil {
+		return
+	}
+	template := config.Git.BranchTemplate
+	if config.Git.Branchbase == "" && (template == "" || strings.Contains(template, "{branchbase}")) {
+		d.warn("branchbase", "not set; branches are named %s", branchName(config.Git, "KEY", "type", "summary"))
+	}
+}
+
+// gitOutput runs git and returns its trimmed output
+func gitOutput(args ...string) (string, error) {
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("%s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// checkTerminal reports what the terminal supports: interactive input, colour and an editor
+func (d *doctor) checkTerminal() {
+	d.begin("terminal", "Terminal")
+
+	if isTerminal(os.Stdin) {
+		d.pass("stdin", "terminal")
+	} else {
+		d.warn("stdin", "not a terminal; interactive prompts read from a pipe")
+	}
+
+	term := os.Getenv("TERM")
+	switch {
+	case term == "" && runtime.GOOS != "windows":
+		d.warn("colour", "TERM is not set")
+	case term == "dumb":
+		d.warn("colour", "TERM=dumb doesn't support colour")
+	default:
+		d.pass("colour", "TERM=%s", term)
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	if _, err := exec.LookPath(strings.Fields(editor)[0]); err != nil {
+		d.fail("editor", "%s not found; set $VISUAL or $EDITOR", editor)
+	} else {
+		d.pass("editor", "%s", editor)
+	}
+}
+
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,354 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/emilsto/jig/jira"
+)
+
+// Names Jira uses for the story points field, depending on the project template
+var storyPointsFieldNames = []string{"Story Points", "Story point estimate"}
+
+// issueDocument is the TOML front matter of an issue opened in $EDITOR; the description follows it as Markdown
+type issueDocument struct {
+	Summary     string   `toml:"summary"`
+	Priority    string   `toml:"priority"`
+	Labels      []string `toml:"labels"`
+	StoryPoints *float64 `toml:"story_points,omitempty"`
+	FixVersions []string `toml:"fix_versions"`
+}
+
+const frontMatterDelim = "+++"
+
+// findEditField resolves a field by ID or (case-insensitive) name in editmeta
+func findEditField(meta map[string]jira.FieldMeta, name string) (jira.FieldMeta, bool) {
+	if field, ok := meta[name]; ok {
+		return field, true
+	}
+	for _, field := range meta {
+		if strings.EqualFold(field.Name, name) {
+			return field, true
+		}
+	}
+	return jira.FieldMeta{}, false
+}
+
+// findStoryPointsField prefers the story_points mapping from config.toml over well known names
+func findStoryPointsField(meta map[string]jira.FieldMeta, fields fieldMap) (jira.FieldMeta, bool) {
+	if id, ok := fields["story_points"]; ok {
+		if field, ok := meta[id]; ok {
+			return field, true
+		}
+	}
+	for _, name := range storyPointsFieldNames {
+		if field, ok := findEditField(meta, name); ok {
+			return field, true
+		}
+	}
+	return jira.FieldMeta{}, false
+}
+
+// setEditField validates a field against editmeta and adds its value to the update
+func setEditField(update *jira.IssueUpdate, meta map[string]jira.FieldMeta, name string, value any) error {
+	field, ok := findEditField(meta, name)
+	if !ok {
+		return fmt.Errorf("field %q cannot be edited on this issue", name)
+	}
+	update.Fields[fieldID(field)] = value
+	return nil
+}
+
+// runEditCommand implements `jig edit KEY [flags]`
+func runEditCommand(args []string) error {
+	var labels, sets stringList
+	fs := flag.NewFlagSet("edit", flag.ExitOnError)
+	summary := fs.String("summary", "", "New summary")
+	priority := fs.String("priority", "", "New priority name")
+	points := fs.String("points", "", "Story points estimate")
+	editor := fs.Bool("e", false, "Edit the issue as a Markdown/TOML document in $EDITOR")
+	fs.Var(&labels, "label", "Add (+name or name) or remove (-name) a label; repeatable")
+	fs.Var(&sets, "set", "Set any editable field, field=value; repeatable")
+
+	issueKey, _, err := parseWithKey(fs, args)
+	if err != nil {
+		return fmt.Errorf("usage: jig edit KEY [--summary S] [--label +x/-y] [--priority P] [--points N] [--set field=value] [-e]")
+	}
+
+	config, client, err := loadClient()
+	if err != nil {
+		return err
+	}
+	fields := loadFieldMap(config, client)
+
+	if *editor {
+		return editIssueInEditor(client, issueKey, fields)
+	}
+
+	ctx := context.Background()
+	meta, err := client.GetEditMeta(ctx, issueKey)
+	if err != nil {
+		return fmt.Errorf("failed to fetch edit metadata: %v", err)
+	}
+
+	update := jira.IssueUpdate{Fields: map[string]any{}, Update: map[string][]map[string]any{}}
+
+	if *summary != "" {
+		if err := setEditField(&update, meta, "summary", *summary); err != nil {
+			return err
+		}
+	}
+
+	if *priority != "" {
+		field, ok := meta["priority"]
+		if !ok {
+			return fmt.Errorf("priority cannot be edited on this issue")
+		}
+		value, err := parseFieldValue(field, *priority)
+		if err != nil {
+			return err
+		}
+		update.Fields["priority"] = value
+	}
+
+	if *points != "" {
+		field, ok := findStoryPointsField(meta, fields)
+		if !ok {
+			return fmt.Errorf("no story points field on this issue")
+		}
+		value, err := parseFieldValue(field, *points)
+		if err != nil {
+			return err
+		}
+		update.Fields[fieldID(field)] = value
+	}
+
+	if len(labels) > 0 {
+		if _, ok := meta["labels"]; !ok {
+			return fmt.Errorf("labels cannot be edited on this issue")
+		}
+		for _, label := range labels {
+			switch {
+			case strings.HasPrefix(label, "-"):
+				update.Update["labels"] = append(update.Update["labels"], map[string]any{"remove": label[1:]})
+			default:
+				update.Update["labels"] = append(update.Update["labels"], map[string]any{"add": strings.TrimPrefix(label, "+")})
+			}
+		}
+	}
+
+	for _, set := range sets {
+		name, raw, ok := strings.Cut(set, "=")
+		if !ok {
+			return fmt.Errorf("invalid --set %q, expected field=value", set)
+		}
+		field, ok := findEditField(meta, fields.resolve(strings.TrimSpace(name)))
+		if !ok {
+			return fmt.Errorf("field %q cannot be edited on this issue", name)
+		}
+		value, err := parseFieldValue(field, raw)
+		if err != nil {
+			return err
+		}
+		update.Fields[fieldID(field)] = value
+	}
+
+	if len(update.Fields) == 0 && len(update.Update) == 0 {
+		return fmt.Errorf("nothing to change; pass flags or -e to edit in $EDITOR")
+	}
+
+	printInfo("Updating %s...", issueKey)
+	if err := client.UpdateIssue(ctx, issueKey, update); err != nil {
+		return err
+	}
+
+	printSuccess("Updated %s", printHighlight(issueKey))
+	return nil
+}
+
+// editIssueInEditor opens the issue as TOML front matter plus a Markdown description and
+// applies whatever changed
+func editIssueInEditor(client *jira.Client, issueKey string, fields fieldMap) error {
+	ctx := context.Background()
+
+	printInfo("Fetching %s...", issueKey)
+	issue, err := client.GetIssueDetails(ctx, issueKey)
+	if err != nil {
+		return err
+	}
+
+	meta, err := client.GetEditMeta(ctx, issueKey)
+	if err != nil {
+		return fmt.Errorf("failed to fetch edit metadata: %v", err)
+	}
+
+	original := issueDocument{
+		Summary:  issue.Fields.Summary,
+		Priority: issue.Fields.Priority.Name,
+		Labels:   issue.Fields.Labels,
+	}
+	for _, version := range issue.Fields.FixVersions {
+		original.FixVersions = append(original.FixVersions, version.Name)
+	}
+
+	pointsField, hasPoints := findStoryPointsField(meta, fields)
+	if hasPoints {
+		values, err := client.GetIssueFields(ctx, issueKey, fieldID(pointsField))
+		if err != nil {
+			return err
+		}
+		if points, ok := values[fieldID(pointsField)].(float64); ok {
+			original.StoryPoints = &points
+		}
+	}
+
+	originalDesc := jira.RenderADFMarkdown(issue.Fields.Description)
+	content, err := formatIssueDocument(original, originalDesc)
+	if err != nil {
+		return err
+	}
+
+	edited, err := editText(content, issueKey+"-*.md")
+	if err != nil {
+		return err
+	}
+
+	doc, desc, err := parseIssueDocument(edited)
+	if err != nil {
+		return err
+	}
+
+	update := jira.IssueUpdate{Fields: map[string]any{}}
+
+	if doc.Summary != original.Summary {
+		if strings.TrimSpace(doc.Summary) == "" {
+			return fmt.Errorf("summary cannot be empty")
+		}
+		if err := setEditField(&update, meta, "summary", doc.Summary); err != nil {
+			return err
+		}
+	}
+
+	if doc.Priority != original.Priority {
+		field, ok := meta["priority"]
+		if !ok {
+			return fmt.Errorf("priority cannot be edited on this issue")
+		}
+		value, err := parseFieldValue(field, doc.Priority)
+		if err != nil {
+			return err
+		}
+		update.Fields["priority"] = value
+	}
+
+	if !sameStrings(doc.Labels, original.Labels) {
+		labels := doc.Labels
+		if labels == nil {
+			labels = []string{}
+		}
+		if err := setEditField(&update, meta, "labels", labels); err != nil {
+			return err
+		}
+	}
+
+	if !sameStrings(doc.FixVersions, original.FixVersions) {
+		versions := []map[string]any{}
+		for _, name := range doc.FixVersions {
+			versions = append(versions, map[string]any{"name": name})
+		}
+		if err := setEditField(&update, meta, "fixVersions", versions); err != nil {
+			return err
+		}
+	}
+
+	if !reflect.DeepEqual(doc.StoryPoints, original.StoryPoints) {
+		if !hasPoints {
+			return fmt.Errorf("no story points field on this issue")
+		}
+		var points any
+		if doc.StoryPoints != nil {
+			points = *doc.StoryPoints
+		}
+		update.Fields[fieldID(pointsField)] = points
+	}
+
+	if desc != strings.TrimSpace(originalDesc) {
+		if err := setEditField(&update, meta, "description", jira.MarkdownToADF(desc)); err != nil {
+			return err
+		}
+	}
+
+	if len(update.Fields) == 0 {
+		fmt.Println("No changes")
+		return nil
+	}
+
+	changed := make([]string, 0, len(update.Fields))
+	for id := range update.Fields {
+		if field, ok := meta[id]; ok {
+			changed = append(changed, field.Name)
+		} else {
+			changed = append(changed, id)
+		}
+	}
+	slices.Sort(changed)
+
+	printInfo("Updating %s (%s)...", issueKey, strings.Join(changed, ", "))
+	if err := client.UpdateIssue(ctx, issueKey, update); err != nil {
+		return err
+	}
+
+	printSuccess("Updated %s", printHighlight(issueKey))
+	return nil
+}
+
+func formatIssueDocument(doc issueDocument, description string) (string, error) {
+	var b bytes.Buffer
+	b.WriteString(frontMatterDelim + "\n")
+	if err := toml.NewEncoder(&b).Encode(doc); err != nil {
+		return "", fmt.Errorf("failed to encode issue: %v", err)
+	}
+	b.WriteString(frontMatterDelim + "\n\n")
+	b.WriteString(description)
+	b.WriteString("\n")
+	return b.String(), nil
+}
+
+func parseIssueDocument(content string) (issueDocument, string, error) {
+	var doc issueDocument
+
+	content = strings.TrimLeft(content, "\n")
+	if !strings.HasPrefix(content, frontMatterDelim+"\n") {
+		return doc, "", fmt.Errorf("missing %s front matter", frontMatterDelim)
+	}
+	rest := strings.TrimPrefix(content, frontMatterDelim+"\n")
+
+	end := strings.Index(rest, "\n"+frontMatterDelim)
+	if end < 0 {
+		return doc, "", fmt.Errorf("unterminated %s front matter", frontMatterDelim)
+	}
+
+	if _, err := toml.Decode(rest[:end], &doc); err != nil {
+		return doc, "", fmt.Errorf("invalid front matter: %v", err)
+	}
+
+	description := rest[end+len(frontMatterDelim)+1:]
+	return doc, strings.TrimSpace(description), nil
+}
+
+func sameStrings(a, b []string) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return slices.Equal(a, b)
+}
+
+func handleEditIssue(ctx *actionContext, issue jira.Issue) error {
+	return editIssueInEditor(ctx.jiraClient, issue.Key, ctx.fields)
+}
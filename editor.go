@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// editText opens the user's editor on a temporary file seeded with initial and
+// returns what was saved
+func editText(initial, pattern string) (string, error) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	file, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString(initial); err != nil {
+		file.Close()
+		return "", fmt.Errorf("failed to write temp file: %v", err)
+	}
+	file.Close()
+
+	// The editor may carry arguments, e.g. "code --wait"
+	parts := strings.Fields(editor)
+	cmd := exec.Command(parts[0], append(parts[1:], file.Name())...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor %s failed: %v", editor, err)
+	}
+
+	content, err := os.ReadFile(file.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to read temp file: %v", err)
+	}
+
+	return string(content), nil
+}
+
+// readMarkdown reads Markdown from path ("-" for stdin), or from an editor session when path is empty
+func readMarkdown(path, initial string) (string, error) {
+	var content string
+
+	switch path {
+	case "":
+		text, err := editText(initial, "jig-*.md")
+		if err != nil {
+			return "", err
+		}
+		content = text
+	case "-":
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", err
+		}
+		content = string(data)
+	default:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		content = string(data)
+	}
+
+	return strings.TrimSpace(content), nil
+}
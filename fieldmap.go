@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/emilsto/jig/jira"
+)
+
+// fieldMap maps friendly names such as story_points to Jira custom field IDs
+type fieldMap map[string]string
+
+// resolve returns the field ID for a friendly name, or name itself when it isn't mapped
+func (m fieldMap) resolve(name string) string {
+	if id, ok := m[strings.ToLower(name)]; ok {
+		return id
+	}
+	return name
+}
+
+// names returns the mapped friendly names in a stable order
+func (m fieldMap) names() []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// loadFieldMap returns the [fields] mapping from config.toml, auto-detecting common
+// custom fields when none are configured
+func loadFieldMap(config *Config, client *jira.Client) fieldMap {
+	fields := fieldMap{}
+	for name, id := range config.Fields {
+		fields[strings.ToLower(name)] = id
+	}
+	if len(fields) > 0 {
+		return fields
+	}
+
+	jiraFields, err := client.GetFields(context.Background())
+	if err != nil {
+		printWarning("Failed to detect custom fields: %v", err)
+		return fields
+	}
+
+	for name, id := range jira.DetectCommonFields(jiraFields) {
+		fields[name] = id
+	}
+	return fields
+}
+
+// fieldLabel turns a friendly name like story_points into "Story Points"
+func fieldLabel(name string) string {
+	words := strings.Fields(strings.ReplaceAll(name, "_", " "))
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// formatFieldValue renders a raw custom field value as a short string
+func formatFieldValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []any:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			if text := formatFieldValue(item); text != "" {
+				parts = append(parts, text)
+			}
+		}
+		return strings.Join(parts, ", ")
+	case map[string]any:
+		if v["type"] == "doc" {
+			text := strings.TrimSpace(jira.ExtractDescription(v))
+			first, _, _ := strings.Cut(text, "\n")
+			return first
+		}
+		// Options carry a value, users a displayName, sprints and versions a name, issues a key
+		for _, key := range []string{"value", "displayName", "name", "key"} {
+			if text, ok := v[key].(string); ok {
+				return text
+			}
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+// fieldFilter is a -where field=value condition on the sprint issue list
+type fieldFilter struct {
+	name  string
+	id    string
+	value string
+}
+
+func parseFieldFilters(exprs []string, fields fieldMap) ([]fieldFilter, error) {
+	var filters []fieldFilter
+	for _, expr := range exprs {
+		name, value, ok := strings.Cut(expr, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid -where %q, expected field=value", expr)
+		}
+		name = strings.TrimSpace(name)
+		id := fields.resolve(name)
+		if !strings.HasPrefix(id, "customfield_") {
+			return nil, fmt.Errorf("unknown field %q in -where; map it under [fields] in config.toml", name)
+		}
+		filters = append(filters, fieldFilter{name: name, id: id, value: strings.TrimSpace(value)})
+	}
+	return filters, nil
+}
+
+// namedFilter returns the field=value conditions of a filter defined under [filters]
+func namedFilter(config *Config, name string) ([]string, error) {
+	if name == "" {
+		return nil, nil
+	}
+	where, ok := config.Filters[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown filter %q; define it under [filters] in .jigrc or config.toml", name)
+	}
+	return where, nil
+}
+
+// matchesFilters reports whether the issue's custom fields satisfy every filter;
+// for multi-value fields any single value may match
+func matchesFilters(issue jira.Issue, filters []fieldFilter) bool {
+	for _, filter := range filters {
+		raw := issue.CustomFields[filter.id]
+
+		values := []any{raw}
+		if list, ok := raw.([]any); ok {
+			values = list
+		}
+
+		matched := false
+		for _, value := range values {
+			if strings.EqualFold(formatFieldValue(value), filter.value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// runFieldsCommand implements `jig fields [-all] [search]` and `jig fields detect`
+func runFieldsCommand(args []string) error {
+	if len(args) > 0 && args[0] == "detect" {
+		return runFieldsDetect()
+	}
+
+	fs := flag.NewFlagSet("fields", flag.ExitOnError)
+	all := fs.Bool("all", false, "Include system fields")
+	fs.Parse(args)
+	search := strings.ToLower(strings.Join(fs.Args(), " "))
+
+	config, client, err := loadClient()
+	if err != nil {
+		return err
+	}
+
+	jiraFields, err := client.GetFields(context.Background())
+	if err != nil {
+		return err
+	}
+	slices.SortFunc(jiraFields, func(a, b jira.Field) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+
+	mapped := map[string]string{}
+	for name, id := range config.Fields {
+		mapped[id] = name
+	}
+
+	fmt.Println()
+	printBold("Fields:")
+	for _, field := range jiraFields {
+		if !*all && !field.Custom {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(field.Name), search) && !strings.Contains(field.ID, search) {
+			continue
+		}
+
+		line := fmt.Sprintf("  %s%-24s%s %-32s %s", colorCyan, field.ID, colorReset, field.Name, field.Schema.Type)
+		if name, ok := mapped[field.ID]; ok {
+			line += fmt.Sprintf("  %s(mapped as %s)%s", colorDim, name, colorReset)
+		}
+		fmt.Println(line)
+	}
+	fmt.Println()
+	printDim("Map a field in config.toml under [fields], e.g. story_points = \"customfield_10016\"")
+	return nil
+}
+
+// runFieldsDetect adds auto-detected common custom fields to config.toml without overriding existing mappings
+func runFieldsDetect() error {
+	config, client, err := loadClient()
+	if err != nil {
+		return err
+	}
+
+	jiraFields, err := client.GetFields(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if config.path == "" {
+		return fmt.Errorf("no config.toml to save the fields to")
+	}
+
+	stored := storedFields(config)
+	added := 0
+	for name, id := range jira.DetectCommonFields(jiraFields) {
+		if _, ok := config.Fields[name]; ok {
+			continue
+		}
+		stored[name] = id
+		printSuccess("%s → %s", name, printHighlight(id))
+		added++
+	}
+
+	if added == 0 {
+		fmt.Println("No new fields detected")
+		return nil
+	}
+
+	if err := saveConfig(config, config.path); err != nil {
+		return err
+	}
+	printSuccess("Saved %d field mapping(s) to %s", added, printHighlight(config.path))
+	return nil
+}
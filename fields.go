@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/emilsto/jig/jira"
+)
+
+const textareaFieldType = "com.atlassian.jira.plugin.system.customfieldtypes:textarea"
+
+// fieldID returns the ID used in API payloads for a field; editmeta only fills in key
+func fieldID(field jira.FieldMeta) string {
+	if field.FieldID != "" {
+		return field.FieldID
+	}
+	return field.Key
+}
+
+// parseFieldValue converts user input into the JSON shape Jira expects for the field's schema
+func parseFieldValue(field jira.FieldMeta, input string) (any, error) {
+	input = strings.TrimSpace(input)
+
+	if len(field.AllowedValues) > 0 {
+		var values []map[string]any
+		for _, part := range strings.Split(input, ",") {
+			value, err := matchAllowedValue(field, strings.TrimSpace(part))
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, map[string]any{"id": value.ID})
+		}
+		if field.Schema.Type == "array" {
+			return values, nil
+		}
+		if len(values) != 1 {
+			return nil, fmt.Errorf("%s takes a single value", field.Name)
+		}
+		return values[0], nil
+	}
+
+	switch field.Schema.Type {
+	case "number":
+		num, err := strconv.ParseFloat(input, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a number", field.Name)
+		}
+		return num, nil
+	case "array":
+		var items []string
+		for _, part := range strings.Split(input, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				items = append(items, part)
+			}
+		}
+		switch field.Schema.Items {
+		case "string":
+			return items, nil
+		case "user":
+			users := make([]jira.UserValue, len(items))
+			for i, item := range items {
+				users[i] = jira.UserValue(item)
+			}
+			return users, nil
+		}
+		var values []map[string]any
+		for _, item := range items {
+			values = append(values, map[string]any{"name": item})
+		}
+		return values, nil
+	case "user":
+		return jira.UserValue(input), nil
+	case "issuelink":
+		return map[string]any{"key": strings.ToUpper(input)}, nil
+	case "option":
+		return map[string]any{"value": input}, nil
+	case "string":
+		if field.Schema.Custom == textareaFieldType || field.Schema.System == "description" || field.Schema.System == "environment" {
+			return jira.MarkdownToADF(input), nil
+		}
+		return input, nil
+	}
+
+	return input, nil
+}
+
+// matchAllowedValue finds an allowed value by list number, ID or (case-insensitive) label
+func matchAllowedValue(field jira.FieldMeta, input string) (jira.AllowedValue, error) {
+	if num, err := strconv.Atoi(input); err == nil && num >= 1 && num <= len(field.AllowedValues) {
+		return field.AllowedValues[num-1], nil
+	}
+	for _, value := range field.AllowedValues {
+		if value.ID == input || strings.EqualFold(value.Label(), input) {
+			return value, nil
+		}
+	}
+	return jira.AllowedValue{}, fmt.Errorf("%q is not a valid value for %s", input, field.Name)
+}
+
+// promptFieldValue asks the user for a field value; it returns nil when the input is left empty
+func promptFieldValue(reader *bufio.Reader, field jira.FieldMeta) (any, error) {
+	if len(field.AllowedValues) > 0 {
+		fmt.Println()
+		printBold("%s:", field.Name)
+		for i, value := range field.AllowedValues {
+			fmt.Printf("  %d. %s\n", i+1, value.Label())
+		}
+	}
+
+	label := fmt.Sprintf("Enter %s", field.Name)
+	switch {
+	case len(field.AllowedValues) > 0 && field.Schema.Type == "array":
+		label += " (comma-separated numbers)"
+	case len(field.AllowedValues) > 0:
+		label += " (number)"
+	case field.Schema.Type == "array":
+		label += " (comma-separated)"
+	case field.Schema.Type == "user":
+		label += " (account ID)"
+	case field.Schema.Type == "date":
+		label += " (YYYY-MM-DD)"
+	}
+	if !field.Required || field.HasDefaultValue {
+		label += " [optional]"
+	}
+
+	printPrompt(label)
+	input, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	input = strings.TrimSpace(input)
+
+	if input == "" {
+		if field.Required && !field.HasDefaultValue {
+			return nil, fmt.Errorf("%s is required", field.Name)
+		}
+		return nil, nil
+	}
+
+	return parseFieldValue(field, input)
+}
@@ -4,6 +4,8 @@ import (
 	"os"
 	"flag"
 	"log"
+	"fmt"
+	"strings"
 )
 
 func handleCommandLine() bool {
@@ -18,17 +20,206 @@ func handleCommandLine() bool {
 				log.Fatal(err)
 			}
 			return true
+		case "export":
+			if err := runExportCommand(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return true
+		case "create":
+			if err := runCreateCommand(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return true
+		case "log":
+			if err := runLogCommand(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return true
+		case "timer":
+			if err := runTimerCommand(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return true
+		case "worklog":
+			if err := runWorklogCommand(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return true
+		case "attachments":
+			if err := runAttachmentsCommand(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return true
+		case "attach":
+			if err := runAttachCommand(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return true
+		case "download":
+			if err := runDownloadCommand(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return true
+		case "link":
+			if err := runLinkCommand(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return true
+		case "unlink":
+			if err := runUnlinkCommand(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return true
+		case "sprint":
+			if err := runSprintCommand(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return true
+		case "backlog":
+			if err := runBacklogCommand(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return true
+		case "board":
+			if err := runBoardCommand(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return true
+		case "assign":
+			if err := runAssignCommand(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return true
+		case "cache":
+			if err := runCacheCommand(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return true
+		case "auth":
+			if err := runAuthCommand(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return true
+		case "config":
+			if err := runConfigCommand(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return true
+		case "hooks":
+			if err := runHooksCommand(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return true
+		case "doctor":
+			if err := runDoctorCommand(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return true
+		case "profile":
+			if err := runProfileCommand(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return true
+		case "fields":
+			if err := runFieldsCommand(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return true
+		case "edit":
+			if err := runEditCommand(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return true
+		case "comment":
+			if err := runCommentCommand(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return true
+		case "describe":
+			if err := runDescribeCommand(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return true
 		}
 	}
 	return false
 }
 
-func parseFlags() (help, epics, oneshot bool) {
-	flagHelp := flag.Bool("h", false, "Show help message")
-	flagEpics := flag.Bool("e", false, "Fetch epics from project")
-	flagOneshot := flag.Bool("o", false, "Run once and exit (oneshot mode)")
+type cliFlags struct {
+	help    bool
+	epics   bool
+	oneshot bool
+	sprint  string
+	where   stringList
+	filter  string
+}
+
+func parseFlags() cliFlags {
+	flags := cliFlags{}
+	flag.BoolVar(&flags.help, "h", false, "Show help message")
+	flag.BoolVar(&flags.epics, "e", false, "Fetch epics from project")
+	flag.BoolVar(&flags.oneshot, "o", false, "Run once and exit (oneshot mode)")
+	flag.StringVar(&flags.sprint, "sprint", "", "Sprint to work on: name, ID, current, next or previous")
+	flag.Var(&flags.where, "where", "Only show issues whose field matches, field=value; repeatable")
+	flag.StringVar(&flags.filter, "filter", "", "Apply a named filter from [filters] in .jigrc or config.toml")
 	flag.Parse()
-	return *flagHelp, *flagEpics, *flagOneshot
+	return flags
+}
+
+
+
+// stringList is a flag that may be given several times, e.g. --label +a --label -b
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
 }
 
+// parseWithKey parses fs allowing the issue key before or after the flags, and returns
+// the key together with any remaining positional arguments
+func parseWithKey(fs *flag.FlagSet, args []string) (string, []string, error) {
+	key := ""
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		key = args[0]
+		args = args[1:]
+	}
 
+	if err := fs.Parse(args); err != nil {
+		return "", nil, err
+	}
+
+	rest := fs.Args()
+	if key == "" && len(rest) > 0 {
+		key, rest = rest[0], rest[1:]
+	}
+	if key == "" {
+		return "", nil, fmt.Errorf("missing issue key")
+	}
+
+	return strings.ToUpper(key), rest, nil
+}
+
+// parseKeyArgs is parseWithKey for commands that take flags after their positional
+// arguments too, e.g. `jig download KEY file.txt -o out`. Like parseSprintRef, it takes
+// the leading positional arguments first and parses the flags that follow them
+func parseKeyArgs(fs *flag.FlagSet, args []string) (string, []string, error) {
+	var positional []string
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return "", nil, err
+	}
+	positional = append(positional, fs.Args()...)
+
+	if len(positional) == 0 {
+		return "", nil, fmt.Errorf("missing issue key")
+	}
+	return strings.ToUpper(positional[0]), positional[1:], nil
+}
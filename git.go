@@ -4,14 +4,41 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"regexp"
 	"strings"
 )
 
-func createGitBranch(branchBase, subtaskKey, description string) error {
-	description = strings.ToLower(description)
-	description = strings.ReplaceAll(description, " ", "-")
+const defaultBranchTemplate = "{branchbase}/{key}/{summary}"
 
-	branchName := fmt.Sprintf("%s/%s/%s", branchBase, subtaskKey, description)
+// issueKeyPattern finds Jira issue keys such as PROJ-123 in branch names and commit messages
+var issueKeyPattern = regexp.MustCompile(`\b[A-Z][A-Z0-9_]+-[0-9]+\b`)
+
+// branchName fills in the branch template; empty parts such as an unset branchbase leave
+// no stray slashes behind
+func branchName(git GitConfig, issueKey, issueType, description string) string {
+	template := git.BranchTemplate
+	if template == "" {
+		template = defaultBranchTemplate
+	}
+
+	slug := func(s string) string {
+		return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(s)), " ", "-")
+	}
+	name := strings.NewReplacer(
+		"{branchbase}", git.Branchbase,
+		"{key}", issueKey,
+		"{type}", slug(issueType),
+		"{summary}", slug(description),
+	).Replace(template)
+
+	for strings.Contains(name, "//") {
+		name = strings.ReplaceAll(name, "//", "/")
+	}
+	return strings.Trim(name, "/")
+}
+
+func createGitBranch(git GitConfig, subtaskKey, issueType, description string) error {
+	branchName := branchName(git, subtaskKey, issueType, description)
 
 	fmt.Printf("\nCreating git branch: %s\n", branchName)
 	cmd := exec.Command("git", "checkout", "-b", branchName)
@@ -11,33 +11,75 @@ import (
 
 // getActiveIssues fetches and filters issues for the current sprint
 func getActiveIssues(ctx *actionContext) ([]jira.Issue, error) {
-	issues, err := ctx.jiraClient.GetSprintIssues(context.Background(), ctx.sprint.ID)
+	issues, err := ctx.jiraClient.GetSprintIssues(context.Background(), ctx.sprint.ID, ctx.config.IssueTypes...)
 	if err != nil {
 		return nil, err
 	}
 
 	activeIssues := []jira.Issue{}
 	for _, issue := range issues {
-		if issue.Fields.Status.Name != "Done" {
+		if showStatusCategory(issue, ctx.categories) && matchesFilters(issue, ctx.filters) {
 			activeIssues = append(activeIssues, issue)
 		}
 	}
 	return activeIssues, nil
 }
 
-// displayIssues prints the list of issues in a table
-func displayIssues(issues []jira.Issue) {
+// isDone reports whether an issue no longer needs work. The status category is used rather
+// than the status name, which varies by workflow ("Closed", "Released") and language
+func isDone(issue jira.Issue) bool {
+	return issue.Fields.Status.StatusCategory.Key == "done"
+}
+
+// showStatusCategory reports whether an issue's status category is one of categories,
+// matched by key or name, or when no categories are given whether it isn't done
+func showStatusCategory(issue jira.Issue, categories []string) bool {
+	if len(categories) == 0 {
+		return !isDone(issue)
+	}
+	category := issue.Fields.Status.StatusCategory
+	for _, c := range categories {
+		if strings.EqualFold(c, category.Key) || strings.EqualFold(c, category.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// displayIssues prints the list of issues in a table, with any configured custom field columns
+func displayIssues(ctx *actionContext, issues []jira.Issue) {
 	fmt.Println()
 	printBold("Active Items (%d):", len(issues))
 
+	columns := ctx.config.Table.Columns
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = strings.ToUpper(fieldLabel(column))
+	}
+
 	maxSummaryLen := 60
-	printTableHeader(maxSummaryLen)
+	printTableHeader(maxSummaryLen, headers)
+	blocked := 0
 	for i, issue := range issues {
 		assignee := issue.Fields.Assignee.DisplayName
 		if assignee == "" {
 			assignee = "Unassigned"
 		}
-		printTableRow(i+1, issue.Key, issue.Fields.Summary, issue.Fields.Status.Name, assignee, maxSummaryLen)
+		extra := make([]string, len(columns))
+		for j, column := range columns {
+			extra[j] = formatFieldValue(issue.CustomFields[ctx.fields.resolve(column)])
+		}
+		key := issue.Key
+		if isBlocked(issue) {
+			key += " ⚠"
+			blocked++
+		}
+		printTableRow(i+1, key, issue.Fields.Summary, issue.Fields.Status, assignee, maxSummaryLen, extra)
+	}
+
+	if blocked > 0 {
+		fmt.Println()
+		printWarning("%d issue(s) blocked by unfinished work", blocked)
 	}
 }
 
@@ -52,6 +94,16 @@ func parseUserInput(input string, maxSelection int) (*userAction, error) {
 		return action, nil
 	}
 
+	if input == "b" || input == "board" {
+		action.showBoard = true
+		return action, nil
+	}
+
+	if input == "n" || input == "new" {
+		action.createIssue = true
+		return action, nil
+	}
+
 	fields := strings.Fields(input)
 	if len(fields) == 0 {
 		return action, nil
@@ -72,6 +124,21 @@ func parseUserInput(input string, maxSelection int) (*userAction, error) {
 	case "-su":
 		action.createSubtask = true
 		fields = fields[:len(fields)-1]
+	case "-c":
+		action.addComment = true
+		fields = fields[:len(fields)-1]
+	case "-e":
+		action.editIssue = true
+		fields = fields[:len(fields)-1]
+	case "-t":
+		action.logWork = true
+		fields = fields[:len(fields)-1]
+	case "-a":
+		action.attachFile = true
+		fields = fields[:len(fields)-1]
+	case "-as":
+		action.assign = true
+		fields = fields[:len(fields)-1]
 	case "-pa":
 		action.getParents = true
 		fields = fields[:len(fields)-1]
@@ -112,18 +179,50 @@ func handleCreateBranch(ctx *actionContext, issue jira.Issue) error {
 		return fmt.Errorf("branch description cannot be empty")
 	}
 
-	if err := createGitBranch(ctx.config.Git.Branchbase, issue.Key, branchDesc); err != nil {
+	if err := createGitBranch(ctx.config.Git, issue.Key, issue.Fields.IssueType.Name, branchDesc); err != nil {
 		return err
 	}
+	if !strings.EqualFold(issue.Fields.Status.Name, ctx.config.StartStatus) {
+		if err := startWork(ctx, issue.Key); err != nil {
+			return err
+		}
+	}
 
 	fmt.Println()
 	printSuccess("Complete")
 	return nil
 }
 
+// startWork moves an issue to the configured start_status, if there is one, once a branch
+// has been created for it
+func startWork(ctx *actionContext, issueKey string) error {
+	status := ctx.config.StartStatus
+	if status == "" {
+		return nil
+	}
+
+	transitions, err := ctx.jiraClient.GetTransitions(context.Background(), issueKey)
+	if err != nil {
+		return err
+	}
+	for _, transition := range transitions {
+		if strings.EqualFold(transition.To.Name, status) || strings.EqualFold(transition.Name, status) {
+			if err := ctx.jiraClient.TransitionIssue(context.Background(), issueKey, transition.ID); err != nil {
+				return err
+			}
+			printSuccess("%s moved to %s", printHighlight(issueKey), printStatus(transition.To.Name, transition.To.StatusCategory.Key))
+			return nil
+		}
+	}
+
+	printWarning("%s can't be moved to %q from its current status", issueKey, status)
+	return nil
+}
+
 func handleChangeStatus(ctx *actionContext, issue jira.Issue) error {
 	printInfo("Getting available transitions for %s...", issue.Key)
-	transitions, err := ctx.jiraClient.GetTransitions(context.Background(), issue.Key)
+	transitions, err := ctx.jiraClient.GetWorkflowTransitions(context.Background(), issue.Key,
+		issue.Fields.IssueType.Name, issue.Fields.Status.ID)
 	if err != nil {
 		return err
 	}
@@ -136,7 +235,8 @@ func handleChangeStatus(ctx *actionContext, issue jira.Issue) error {
 	fmt.Println()
 	printBold("Available Transitions:")
 	for i, transition := range transitions {
-		fmt.Printf("  %d. %s → %s\n", i+1, printStatus(issue.Fields.Status.Name), printStatus(transition.To.Name))
+		fmt.Printf("  %d. %s → %s\n", i+1, printStatus(issue.Fields.Status.Name, issue.Fields.Status.StatusCategory.Key),
+			printStatus(transition.To.Name, transition.To.StatusCategory.Key))
 	}
 
 	fmt.Println()
@@ -163,7 +263,8 @@ func handleChangeStatus(ctx *actionContext, issue jira.Issue) error {
 		return err
 	}
 
-	printSuccess("Status changed: %s → %s", printStatus(issue.Fields.Status.Name), printStatus(selectedTransition.To.Name))
+	printSuccess("Status changed: %s → %s", printStatus(issue.Fields.Status.Name, issue.Fields.Status.StatusCategory.Key),
+		printStatus(selectedTransition.To.Name, selectedTransition.To.StatusCategory.Key))
 	return nil
 }
 
@@ -179,10 +280,29 @@ func handleCreateSubtask(ctx *actionContext, issue jira.Issue) error {
 		return fmt.Errorf("subtask summary cannot be empty")
 	}
 
+	printPrompt("Enter subtask description (optional, 'e' to open $EDITOR)")
+	descInput, err := ctx.reader.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	descInput = strings.TrimSpace(descInput)
+
+	if descInput == "e" {
+		descInput, err = readMarkdown("", "")
+		if err != nil {
+			return err
+		}
+	}
+
+	var description *jira.ADFNode
+	if descInput != "" {
+		description = jira.MarkdownToADF(descInput)
+	}
+
 	fmt.Println()
 	printInfo("Creating subtask for %s...", issue.Key)
 
-	subtaskKey, err := ctx.jiraClient.CreateSubtask(context.Background(), issue.Key, summary)
+	subtaskKey, err := ctx.jiraClient.CreateSubtask(context.Background(), issue.Key, summary, description)
 	if err != nil {
 		return err
 	}
@@ -201,7 +321,10 @@ func handleCreateSubtask(ctx *actionContext, issue jira.Issue) error {
 		return fmt.Errorf("branch description cannot be empty")
 	}
 
-	if err := createGitBranch(ctx.config.Git.Branchbase, subtaskKey, branchDesc); err != nil {
+	if err := createGitBranch(ctx.config.Git, subtaskKey, "subtask", branchDesc); err != nil {
+		return err
+	}
+	if err := startWork(ctx, subtaskKey); err != nil {
 		return err
 	}
 
@@ -210,12 +333,31 @@ func handleCreateSubtask(ctx *actionContext, issue jira.Issue) error {
 	return nil
 }
 
+func handleAddComment(ctx *actionContext, issue jira.Issue) error {
+	body, err := readMarkdown("", "")
+	if err != nil {
+		return err
+	}
+	if body == "" {
+		fmt.Println("Empty comment, cancelled")
+		return nil
+	}
+
+	printInfo("Adding comment to %s...", issue.Key)
+	if err := ctx.jiraClient.AddComment(context.Background(), issue.Key, jira.MarkdownToADF(body)); err != nil {
+		return err
+	}
+
+	printSuccess("Comment added to %s", printHighlight(issue.Key))
+	return nil
+}
+
 func handleShowDetails(ctx *actionContext, issue jira.Issue) error {
 	printInfo("Fetching issue details for %s...", issue.Key)
 	issueDetails, err := ctx.jiraClient.GetIssueDetails(context.Background(), issue.Key)
 	if err != nil {
 		return err
 	}
-	printIssueDetails(issueDetails, jira.ExtractDescription)
+	printIssueDetails(issueDetails, jira.RenderADF, ctx.fields)
 	return nil
 }
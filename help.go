@@ -10,20 +10,69 @@ func printHelp() {
 	fmt.Println()
 	printInfo("Commands:")
 	fmt.Println("  init                  Create .jigrc file in current directory")
+	fmt.Println("  auth login|logout|status  Keep the API token in the keyring or pass")
+	fmt.Println("  auth login --oauth    Log in with OAuth 2.0 in the browser (-client-id, -port)")
+	fmt.Println("  create [flags]        Create a story, task, bug or epic")
+	fmt.Println("                          -type T -summary S -f desc.md -e -sprint -me")
+	fmt.Println("  edit KEY [flags]      Edit fields: --summary, --label +x/-y, --priority,")
+	fmt.Println("                          --points, --set field=value, -e ($EDITOR)")
+	fmt.Println("  assign KEY @name      Assign to a teammate (fuzzy match), 'me' or 'none'")
+	fmt.Println("  log KEY 1h30m [note]  Log time on an issue (-d YYYY-MM-DD for another day)")
+	fmt.Println("  timer start KEY       Start a timer; stop logs the elapsed time")
+	fmt.Println("  timer stop|status|cancel")
+	fmt.Println("  worklog [-last]       Report my logged hours per issue and day this week")
+	fmt.Println("  attachments KEY       List attachments on an issue")
+	fmt.Println("  attach KEY FILE...    Upload files (logs, screenshots) to an issue")
+	fmt.Println("  download KEY [NAME..] Download attachments by name or number (-all, -o dir)")
+	fmt.Println("  link A <relation> B   Link issues, e.g. 'link PROJ-1 blocks PROJ-2'")
+	fmt.Println("  unlink A B            Remove all links between two issues")
+	fmt.Println("  sprint list [-open]   List the board's sprints, including closed ones")
+	fmt.Println("  sprint show [SPRINT]  Show a sprint's issues (ID, name, current, next, previous)")
+	fmt.Println("  sprint move KEY... T  Move issues to a sprint or 'backlog', e.g. 'move PROJ-1 next'")
+	fmt.Println("  sprint start|close    Start the next sprint (-days, -goal) or close the current one")
+	fmt.Println("                          (-move next|backlog carries over incomplete issues)")
+	fmt.Println("  board [-sprint S]     Show the sprint (or kanban board) in its board columns")
+	fmt.Println("  backlog               Groom the backlog: rerank, estimate, pull into a sprint")
+	fmt.Println("  backlog A above|below B, backlog KEY est 3, backlog KEY... pull [next]")
+	fmt.Println("  fields [-all] [text]  List custom fields and their IDs")
+	fmt.Println("  fields detect         Map common custom fields (story points, epic link, ...)")
+	fmt.Println("  config sources        Show each setting and where it came from")
+	fmt.Println("  config get|set KEY    Read or change a setting, e.g. 'config set git.branchbase main'")
+	fmt.Println("  config show|edit      Print the configuration in effect (--origin: with sources),")
+	fmt.Println("                          or edit config.toml safely")
+	fmt.Println("  config validate       Check config.toml, .jigrc, credentials, projects and boards")
+	fmt.Println("  config add-board      Add projects and boards, as in the first-run setup")
+	fmt.Println("  profile list|add|use  Switch between Jira sites and accounts ([profiles.<name>])")
+	fmt.Println("  hooks install         Install the git hooks enabled under [hooks] in .jigrc")
+	fmt.Println("  doctor [-json]        Check config, Jira access, permissions, git and the terminal")
+	fmt.Println("  cache clear|path      Forget cached Jira metadata (users, issue types, fields, ...)")
+	fmt.Println("  export KEY [-out f]   Print issue as Markdown (or write to file)")
+	fmt.Println("  comment KEY [-f f]    Add a Markdown comment (from file, '-' or $EDITOR)")
+	fmt.Println("  describe KEY [-f f]   Replace the description with Markdown")
 	fmt.Println("  h                     Show this help message")
 	fmt.Println()
 	printInfo("Flags:")
 	fmt.Println("  -h                    Show this help message")
 	fmt.Println("  -e                    Fetch epics from project")
 	fmt.Println("  -o                    Run once and exit (oneshot mode)")
+	fmt.Println("  -sprint SPRINT        Work on another sprint (name, ID, current, next, previous)")
+	fmt.Println("  -where field=value    Only list issues whose custom field matches (repeatable)")
+	fmt.Println("  -filter NAME          Apply a named filter from [filters] in .jigrc or config.toml")
+	fmt.Println("  --config FILE         Use this config file instead of searching for one (also JIG_CONFIG)")
+	fmt.Println("  --profile NAME        Use a profile from config.toml (also JIG_PROFILE, or profile in .jigrc)")
+	fmt.Println("  --project, --board, --baseurl, --agileurl, --email, --token, --branchbase")
+	fmt.Println("                        Override config, given before the command (also JIG_PROJECT, JIG_BOARD,")
+	fmt.Println("                        JIG_API_BASEURL, JIG_API_AGILEURL, JIG_API_EMAIL, JIG_API_TOKEN,")
+	fmt.Println("                        JIG_GIT_BRANCHBASE)")
 	fmt.Println()
 	printInfo("Configuration:")
-	fmt.Println("  Global: ~/.config/jig/config.toml")
-	fmt.Println("  Per-directory: .jigrc (auto-detected from current dir upwards)")
+	fmt.Println("  Global: $XDG_CONFIG_HOME/jig/config.toml (~/.config/jig/config.toml)")
+	fmt.Println("  Per-directory: .jigrc (merged from the repository root down to the current dir);")
+	fmt.Println("                 project, board and team conventions such as branch_template and filters")
 	fmt.Println()
 	printInfo("Interactive Mode:")
 	fmt.Println("  When run without -o flag, jig will:")
-	fmt.Println("  1. Fetch the latest sprint and active issues")
+	fmt.Println("  1. Fetch the active sprint (or the next one) and its open issues")
 	fmt.Println("  2. Allow you to select an issue to create a subtask")
 	fmt.Println("  3. Loop back to step 1 after each action (continuous mode)")
 	fmt.Println("  With -o flag, jig exits after completing one action")
@@ -32,6 +81,9 @@ func printHelp() {
 	fmt.Println("  jig init              Create .jigrc for this directory")
 	fmt.Println("  jig                   Run in continuous interactive mode")
 	fmt.Println("  jig -o                Run once and exit (oneshot mode)")
+	fmt.Println("  jig create -type Bug -sprint -me")
+	fmt.Println("                        Create a bug in the current sprint, assigned to you")
+	fmt.Println("  jig export PROJ-123   Export issue as Markdown")
 	fmt.Println("  jig -e                Fetch epics")
 	fmt.Println("  jig h                 Show help")
 }
@@ -42,9 +94,16 @@ func printInteractiveHelp() {
 	fmt.Println("  - Enter a number (1-N) to select an issue")
 	fmt.Println("  - Add -p after the number to assign to yourself (e.g., '3 -p')")
 	fmt.Println("  - Add -s after the number to change status (e.g., '3 -s')")
+	fmt.Println("  - Add -as after the number to assign to someone else (e.g., '3 -as')")
 	fmt.Println("  - Add -g after the number to create git branch for issue (e.g., '3 -g')")
 	fmt.Println("  - Add -su after the number to create subtask + branch (e.g., '3 -su')")
+	fmt.Println("  - Add -c after the number to write a comment in $EDITOR (e.g., '3 -c')")
+	fmt.Println("  - Add -e after the number to edit the issue in $EDITOR (e.g., '3 -e')")
+	fmt.Println("  - Add -t after the number to log time spent (e.g., '3 -t')")
+	fmt.Println("  - Add -a after the number to attach files (e.g., '3 -a')")
+	fmt.Println("  - Enter n to create a new issue in the current project")
 	fmt.Println("  - Enter -l to refresh and list sprint tickets")
+	fmt.Println("  - Enter b to show the sprint as board columns")
 	fmt.Println("  - Enter 0 or q to exit without selecting")
 	fmt.Println("  - Enter h to show this help message")
 	fmt.Println()
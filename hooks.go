@@ -0,0 +1,197 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const hooksUsage = "usage: jig hooks install | prepare-commit-msg FILE [SOURCE] | commit-msg FILE"
+
+const defaultCommitFormat = "{key}: {message}"
+
+// hookMarker identifies hook scripts written by jig, which it may replace or remove
+const hookMarker = "# installed by jig"
+
+// runHooksCommand implements `jig hooks`: install writes the git hooks enabled under [hooks]
+// in .jigrc, and the others are what those hooks run
+func runHooksCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf(hooksUsage)
+	}
+
+	hooks, format, err := loadHookSettings()
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "install":
+		return installHooks(hooks)
+	case "prepare-commit-msg":
+		if len(args) < 2 {
+			return fmt.Errorf(hooksUsage)
+		}
+		if !hooks.PrepareCommitMsg {
+			return nil
+		}
+		source := ""
+		if len(args) > 2 {
+			source = args[2]
+		}
+		return prepareCommitMsg(args[1], source, format)
+	case "commit-msg":
+		if len(args) < 2 {
+			return fmt.Errorf(hooksUsage)
+		}
+		if !hooks.CommitMsg {
+			return nil
+		}
+		return checkCommitMsg(args[1])
+	default:
+		return fmt.Errorf("unknown hooks command %q; %s", args[0], hooksUsage)
+	}
+}
+
+// loadHookSettings reads the hooks and commit format from .jigrc and config.toml directly;
+// hooks run on every commit, so they skip profiles and the credential store
+func loadHookSettings() (HooksConfig, string, error) {
+	var hooks HooksConfig
+	format := ""
+
+	jigrc, _, err := loadMergedJigRC()
+	if err != nil {
+		return hooks, "", err
+	}
+	if jigrc != nil {
+		hooks = jigrc.Hooks
+		format = jigrc.Git.CommitFormat
+	}
+	if configPath := findConfig("config.toml"); format == "" && configPath != "" {
+		config, err := loadConfig(configPath)
+		if err != nil {
+			return hooks, "", err
+		}
+		format = config.Git.CommitFormat
+	}
+
+	if format == "" {
+		format = defaultCommitFormat
+	}
+	return hooks, format, nil
+}
+
+// installHooks writes a script for each enabled hook and removes jig's scripts for the
+// others. Hooks jig didn't write are left alone
+func installHooks(hooks HooksConfig) error {
+	dir, err := gitOutput("rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return fmt.Errorf("not in a git repository: %v", err)
+	}
+
+	installed := 0
+	for _, hook := range []struct {
+		name    string
+		enabled bool
+	}{{"prepare-commit-msg", hooks.PrepareCommitMsg}, {"commit-msg", hooks.CommitMsg}} {
+		name, enabled := hook.name, hook.enabled
+		path := filepath.Join(dir, name)
+		existing, err := os.ReadFile(path)
+		if err == nil && !strings.Contains(string(existing), hookMarker) {
+			if enabled {
+				printWarning("%s already exists and wasn't written by jig; add 'jig hooks %s \"$@\"' to it", path, name)
+			}
+			continue
+		}
+
+		if !enabled {
+			if err == nil {
+				if err := os.Remove(path); err != nil {
+					return err
+				}
+				printSuccess("Removed %s", path)
+			}
+			continue
+		}
+
+		script := fmt.Sprintf("#!/bin/sh\n%s; see [hooks] in .jigrc\nexec jig hooks %s \"$@\"\n", hookMarker, name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+			return fmt.Errorf("failed to write %s: %v", path, err)
+		}
+		printSuccess("Installed %s", printHighlight(path))
+		installed++
+	}
+
+	if installed == 0 && !hooks.PrepareCommitMsg && !hooks.CommitMsg {
+		fmt.Println("No hooks are enabled under [hooks] in .jigrc")
+	}
+	return nil
+}
+
+// prepareCommitMsg formats the commit message with the issue key from the branch name,
+// unless the message already mentions it. Merges, squashes and amends are left alone
+func prepareCommitMsg(file, source, format string) error {
+	if source == "merge" || source == "squash" || source == "commit" {
+		return nil
+	}
+
+	branch, err := gitOutput("symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return nil
+	}
+	key := issueKeyPattern.FindString(branch)
+	if key == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(data), "\n")
+	if strings.Contains(commitMessage(lines), key) {
+		return nil
+	}
+
+	message := ""
+	if !strings.HasPrefix(lines[0], "#") {
+		message = lines[0]
+		lines = lines[1:]
+	}
+	subject := strings.NewReplacer("{key}", key, "{message}", message).Replace(format)
+	lines = append([]string{subject}, lines...)
+
+	return os.WriteFile(file, []byte(strings.Join(lines, "\n")), 0644)
+}
+
+// checkCommitMsg rejects a commit message without an issue key; merges are exempt
+func checkCommitMsg(file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	message := commitMessage(strings.Split(string(data), "\n"))
+	if strings.HasPrefix(message, "Merge ") || issueKeyPattern.MatchString(message) {
+		return nil
+	}
+	return fmt.Errorf("the commit message doesn't mention an issue key such as PROJ-123 (required by [hooks] in .jigrc)")
+}
+
+// commitMessage returns the lines of a commit message file that aren't git's comments
+func commitMessage(lines []string) string {
+	var kept []string
+	for _, line := range lines {
+		if strings.HasPrefix(line, "# ------------------------ >8") {
+			break
+		}
+		if !strings.HasPrefix(line, "#") {
+			kept = append(kept, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
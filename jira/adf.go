@@ -0,0 +1,849 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ADFNode is a single node of an Atlassian Document Format tree
+type ADFNode struct {
+	Type    string         `json:"type"`
+	Version int            `json:"version,omitempty"`
+	Text    string         `json:"text,omitempty"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+	Marks   []ADFMark      `json:"marks,omitempty"`
+	Content []ADFNode      `json:"content,omitempty"`
+}
+
+// ADFMark is a text decoration such as strong, em, code or link
+type ADFMark struct {
+	Type  string         `json:"type"`
+	Attrs map[string]any `json:"attrs,omitempty"`
+}
+
+const (
+	ansiReset     = "\033[0m"
+	ansiBold      = "\033[1m"
+	ansiDim       = "\033[2m"
+	ansiItalic    = "\033[3m"
+	ansiUnderline = "\033[4m"
+	ansiStrike    = "\033[9m"
+	ansiRed       = "\033[31m"
+	ansiGreen     = "\033[32m"
+	ansiYellow    = "\033[33m"
+	ansiBlue      = "\033[34m"
+	ansiMagenta   = "\033[35m"
+	ansiCyan      = "\033[36m"
+)
+
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;]*m|\x1b]8;;[^\x1b]*\x1b\\`)
+
+// ParseADF converts a decoded JSON description (as found in DetailedIssue) into an ADF tree
+func ParseADF(doc any) (*ADFNode, error) {
+	if node, ok := doc.(*ADFNode); ok {
+		return node, nil
+	}
+	if node, ok := doc.(ADFNode); ok {
+		return &node, nil
+	}
+
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var node ADFNode
+	if err := json.Unmarshal(raw, &node); err != nil {
+		return nil, err
+	}
+	if node.Type == "" {
+		return nil, fmt.Errorf("not an ADF document")
+	}
+	return &node, nil
+}
+
+// RenderADF renders a description or comment body as styled terminal text
+func RenderADF(doc any) string {
+	if doc == nil {
+		return ""
+	}
+	if str, ok := doc.(string); ok {
+		return str
+	}
+
+	node, err := ParseADF(doc)
+	if err != nil {
+		return ExtractDescription(doc)
+	}
+
+	r := &terminalRenderer{}
+	return strings.TrimRight(strings.Join(r.blocks(node.Content), "\n"), "\n")
+}
+
+// RenderADFMarkdown renders a description or comment body as GitHub-flavoured Markdown
+func RenderADFMarkdown(doc any) string {
+	if doc == nil {
+		return ""
+	}
+	if str, ok := doc.(string); ok {
+		return str
+	}
+
+	node, err := ParseADF(doc)
+	if err != nil {
+		return ExtractDescription(doc)
+	}
+
+	r := &markdownRenderer{}
+	return strings.TrimRight(strings.Join(r.blocks(node.Content), "\n"), "\n")
+}
+
+// visibleWidth returns the printed width of s, ignoring escape sequences
+func visibleWidth(s string) int {
+	return utf8.RuneCountInString(ansiPattern.ReplaceAllString(s, ""))
+}
+
+func attrString(attrs map[string]any, key string) string {
+	if attrs == nil {
+		return ""
+	}
+	switch v := attrs[key].(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	}
+	return ""
+}
+
+func attrInt(attrs map[string]any, key string, def int) int {
+	if attrs == nil {
+		return def
+	}
+	switch v := attrs[key].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	case string:
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return def
+}
+
+// formatADFDate turns the millisecond timestamp of a date node into YYYY-MM-DD
+func formatADFDate(attrs map[string]any) string {
+	ts := attrString(attrs, "timestamp")
+	ms, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ts
+	}
+	return time.UnixMilli(ms).UTC().Format("2006-01-02")
+}
+
+// mentionName returns the display text of a mention node without the leading @
+func mentionName(attrs map[string]any) string {
+	name := strings.TrimPrefix(attrString(attrs, "text"), "@")
+	if name == "" {
+		name = attrString(attrs, "id")
+	}
+	return name
+}
+
+// plainText concatenates the text of all descendants of node
+func plainText(node ADFNode) string {
+	var b strings.Builder
+	var walk func(n ADFNode)
+	walk = func(n ADFNode) {
+		switch n.Type {
+		case "text":
+			b.WriteString(n.Text)
+		case "hardBreak":
+			b.WriteString("\n")
+		case "mention":
+			b.WriteString("@" + mentionName(n.Attrs))
+		case "emoji":
+			if t := attrString(n.Attrs, "text"); t != "" {
+				b.WriteString(t)
+			} else {
+				b.WriteString(attrString(n.Attrs, "shortName"))
+			}
+		case "inlineCard":
+			b.WriteString(attrString(n.Attrs, "url"))
+		case "date":
+			b.WriteString(formatADFDate(n.Attrs))
+		case "status":
+			b.WriteString(attrString(n.Attrs, "text"))
+		}
+		for _, c := range n.Content {
+			walk(c)
+		}
+	}
+	walk(node)
+	return b.String()
+}
+
+// --- Terminal renderer ---
+
+type terminalRenderer struct {
+	listDepth int
+}
+
+var bulletGlyphs = []string{"•", "◦", "▪"}
+
+var panelStyles = map[string]struct {
+	color string
+	label string
+}{
+	"info":    {ansiBlue, "ℹ Info"},
+	"note":    {ansiMagenta, "✎ Note"},
+	"warning": {ansiYellow, "⚠ Warning"},
+	"success": {ansiGreen, "✓ Success"},
+	"error":   {ansiRed, "✗ Error"},
+}
+
+// blocks renders a sequence of block nodes separated by blank lines
+func (r *terminalRenderer) blocks(nodes []ADFNode) []string {
+	var lines []string
+	for i, n := range nodes {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, r.block(n)...)
+	}
+	return lines
+}
+
+// tightBlocks renders block nodes without blank lines, as used inside list items
+func (r *terminalRenderer) tightBlocks(nodes []ADFNode) []string {
+	var lines []string
+	for _, n := range nodes {
+		lines = append(lines, r.block(n)...)
+	}
+	return lines
+}
+
+func (r *terminalRenderer) block(n ADFNode) []string {
+	switch n.Type {
+	case "paragraph":
+		return strings.Split(r.inline(n.Content, ""), "\n")
+
+	case "heading":
+		style := ansiBold
+		if attrInt(n.Attrs, "level", 1) <= 2 {
+			style = ansiBold + ansiCyan
+		}
+		lines := strings.Split(r.inline(n.Content, style), "\n")
+		for i, line := range lines {
+			lines[i] = style + line + ansiReset
+		}
+		return lines
+
+	case "bulletList":
+		glyph := bulletGlyphs[r.listDepth%len(bulletGlyphs)]
+		var lines []string
+		for _, item := range n.Content {
+			lines = append(lines, r.listItem(item, glyph+" ")...)
+		}
+		return lines
+
+	case "orderedList":
+		start := attrInt(n.Attrs, "order", 1)
+		last := strconv.Itoa(start + len(n.Content) - 1)
+		var lines []string
+		for i, item := range n.Content {
+			num := strconv.Itoa(start + i)
+			prefix := strings.Repeat(" ", len(last)-len(num)) + num + ". "
+			lines = append(lines, r.listItem(item, prefix)...)
+		}
+		return lines
+
+	case "taskList":
+		var lines []string
+		for _, item := range n.Content {
+			if item.Type == "taskList" {
+				lines = append(lines, indentLines(r.block(item), "  ")...)
+				continue
+			}
+			box := "☐ "
+			if attrString(item.Attrs, "state") == "DONE" {
+				box = ansiGreen + "☑" + ansiReset + " "
+			}
+			lines = append(lines, prefixLines(strings.Split(r.inline(item.Content, ""), "\n"), box, "  ")...)
+		}
+		return lines
+
+	case "decisionList":
+		var lines []string
+		for _, item := range n.Content {
+			mark := ansiMagenta + "◆" + ansiReset + " "
+			lines = append(lines, prefixLines(strings.Split(r.inline(item.Content, ""), "\n"), mark, "  ")...)
+		}
+		return lines
+
+	case "codeBlock":
+		var lines []string
+		if lang := attrString(n.Attrs, "language"); lang != "" {
+			lines = append(lines, "  "+ansiDim+lang+ansiReset)
+		}
+		code := strings.TrimRight(plainText(n), "\n")
+		for _, line := range strings.Split(code, "\n") {
+			lines = append(lines, "  "+ansiDim+"│"+ansiReset+" "+ansiCyan+line+ansiReset)
+		}
+		return lines
+
+	case "blockquote":
+		return prefixLines(r.blocks(n.Content), ansiDim+"┃"+ansiReset+" ", ansiDim+"┃"+ansiReset+" ")
+
+	case "rule":
+		return []string{ansiDim + strings.Repeat("─", 40) + ansiReset}
+
+	case "panel":
+		style, ok := panelStyles[attrString(n.Attrs, "panelType")]
+		if !ok {
+			style = panelStyles["info"]
+		}
+		bar := style.color + "│" + ansiReset + " "
+		lines := []string{style.color + "│ " + ansiBold + style.label + ansiReset}
+		return append(lines, prefixLines(r.blocks(n.Content), bar, bar)...)
+
+	case "expand", "nestedExpand":
+		title := attrString(n.Attrs, "title")
+		if title == "" {
+			title = "Details"
+		}
+		lines := []string{ansiBold + "▾ " + title + ansiReset}
+		return append(lines, indentLines(r.blocks(n.Content), "  ")...)
+
+	case "table":
+		return r.table(n)
+
+	case "mediaSingle", "mediaGroup":
+		var lines []string
+		for _, c := range n.Content {
+			lines = append(lines, r.block(c)...)
+		}
+		return lines
+
+	case "media":
+		name := attrString(n.Attrs, "alt")
+		if name == "" {
+			name = attrString(n.Attrs, "id")
+		}
+		return []string{ansiDim + "[attachment: " + name + "]" + ansiReset}
+
+	case "blockCard", "embedCard":
+		url := attrString(n.Attrs, "url")
+		return []string{hyperlink(url, ansiBlue+ansiUnderline+url+ansiReset)}
+	}
+
+	if len(n.Content) > 0 && isInline(n.Content[0]) {
+		return strings.Split(r.inline(n.Content, ""), "\n")
+	}
+	if n.Text != "" {
+		return strings.Split(r.inline([]ADFNode{n}, ""), "\n")
+	}
+	return r.blocks(n.Content)
+}
+
+// listItem renders a list item with the marker on its first line and hanging indentation after
+func (r *terminalRenderer) listItem(item ADFNode, marker string) []string {
+	r.listDepth++
+	lines := r.tightBlocks(item.Content)
+	r.listDepth--
+
+	if len(lines) == 0 {
+		lines = []string{""}
+	}
+	pad := strings.Repeat(" ", visibleWidth(marker))
+	return prefixLines(lines, ansiDim+marker+ansiReset, pad)
+}
+
+// table renders a box-drawn table, sizing each column to its widest cell
+func (r *terminalRenderer) table(n ADFNode) []string {
+	type cell struct {
+		lines  []string
+		header bool
+	}
+
+	var rows [][]cell
+	cols := 0
+	for _, row := range n.Content {
+		var cells []cell
+		for _, c := range row.Content {
+			cells = append(cells, cell{
+				lines:  r.tightBlocks(c.Content),
+				header: c.Type == "tableHeader",
+			})
+		}
+		if len(cells) > cols {
+			cols = len(cells)
+		}
+		rows = append(rows, cells)
+	}
+	if cols == 0 {
+		return nil
+	}
+
+	widths := make([]int, cols)
+	for _, row := range rows {
+		for i, c := range row {
+			for _, line := range c.lines {
+				if w := visibleWidth(line); w > widths[i] {
+					widths[i] = w
+				}
+			}
+		}
+	}
+
+	border := func(left, mid, right string) string {
+		parts := make([]string, cols)
+		for i, w := range widths {
+			parts[i] = strings.Repeat("─", w+2)
+		}
+		return ansiDim + left + strings.Join(parts, mid) + right + ansiReset
+	}
+	bar := ansiDim + "│" + ansiReset
+
+	lines := []string{border("┌", "┬", "┐")}
+	for ri, row := range rows {
+		height := 1
+		for _, c := range row {
+			if len(c.lines) > height {
+				height = len(c.lines)
+			}
+		}
+
+		for h := 0; h < height; h++ {
+			var b strings.Builder
+			b.WriteString(bar)
+			for i := 0; i < cols; i++ {
+				text := ""
+				header := false
+				if i < len(row) {
+					header = row[i].header
+					if h < len(row[i].lines) {
+						text = row[i].lines[h]
+					}
+				}
+				if header {
+					text = ansiBold + text + ansiReset
+				}
+				b.WriteString(" " + text + strings.Repeat(" ", widths[i]-visibleWidth(text)) + " " + bar)
+			}
+			lines = append(lines, b.String())
+		}
+
+		// Separate header rows from the body
+		headerRow := len(row) > 0 && row[0].header
+		nextHeader := ri < len(rows)-1 && len(rows[ri+1]) > 0 && rows[ri+1][0].header
+		if headerRow && ri < len(rows)-1 && !nextHeader {
+			lines = append(lines, border("├", "┼", "┤"))
+		}
+	}
+	return append(lines, border("└", "┴", "┘"))
+}
+
+// inline renders inline nodes; base is the style to restore after each styled span
+func (r *terminalRenderer) inline(nodes []ADFNode, base string) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		switch n.Type {
+		case "text":
+			b.WriteString(styleText(n, base))
+		case "hardBreak":
+			b.WriteString("\n")
+		case "mention":
+			b.WriteString(ansiBold + ansiCyan + "@" + mentionName(n.Attrs) + ansiReset + base)
+		case "emoji", "date":
+			b.WriteString(plainText(n))
+		case "status":
+			b.WriteString(ansiBold + "[" + strings.ToUpper(attrString(n.Attrs, "text")) + "]" + ansiReset + base)
+		case "inlineCard":
+			url := attrString(n.Attrs, "url")
+			b.WriteString(hyperlink(url, ansiBlue+ansiUnderline+url+ansiReset+base))
+		default:
+			if len(n.Content) > 0 {
+				b.WriteString(r.inline(n.Content, base))
+			} else {
+				b.WriteString(n.Text)
+			}
+		}
+	}
+	return b.String()
+}
+
+// styleText applies the marks of a text node as ANSI styles and OSC 8 hyperlinks
+func styleText(n ADFNode, base string) string {
+	style := ""
+	href := ""
+	for _, m := range n.Marks {
+		switch m.Type {
+		case "strong":
+			style += ansiBold
+		case "em":
+			style += ansiItalic
+		case "underline":
+			style += ansiUnderline
+		case "strike":
+			style += ansiStrike
+		case "code":
+			style += ansiCyan
+		case "link":
+			href = attrString(m.Attrs, "href")
+			style += ansiBlue + ansiUnderline
+		}
+	}
+
+	text := n.Text
+	if style != "" {
+		text = style + text + ansiReset + base
+	}
+	if href != "" {
+		text = hyperlink(href, text)
+	}
+	return text
+}
+
+// hyperlink wraps text in an OSC 8 terminal hyperlink
+func hyperlink(url, text string) string {
+	if url == "" {
+		return text
+	}
+	return "\033]8;;" + url + "\033\\" + text + "\033]8;;\033\\"
+}
+
+func isInline(n ADFNode) bool {
+	switch n.Type {
+	case "text", "hardBreak", "mention", "emoji", "date", "status", "inlineCard", "placeholder":
+		return true
+	}
+	return false
+}
+
+// prefixLines prefixes the first line with first and every following line with rest
+func prefixLines(lines []string, first, rest string) []string {
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		if i == 0 {
+			out[i] = first + line
+		} else if line == "" {
+			out[i] = strings.TrimRight(rest, " ")
+		} else {
+			out[i] = rest + line
+		}
+	}
+	return out
+}
+
+func indentLines(lines []string, indent string) []string {
+	return prefixLines(lines, indent, indent)
+}
+
+// --- Markdown renderer ---
+
+type markdownRenderer struct{}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"*", `\*`,
+	"_", `\_`,
+	"[", `\[`,
+	"]", `\]`,
+	"~", `\~`,
+)
+
+var markdownLineStart = regexp.MustCompile(`^(\s*)([#>+-]|\d+[.)]|\|)`)
+
+func (r *markdownRenderer) blocks(nodes []ADFNode) []string {
+	var lines []string
+	for i, n := range nodes {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, r.block(n)...)
+	}
+	return lines
+}
+
+func (r *markdownRenderer) tightBlocks(nodes []ADFNode) []string {
+	var lines []string
+	for i, n := range nodes {
+		// A paragraph following another paragraph needs a blank line to stay separate
+		if i > 0 && n.Type == "paragraph" && nodes[i-1].Type == "paragraph" {
+			lines = append(lines, "")
+		}
+		lines = append(lines, r.block(n)...)
+	}
+	return lines
+}
+
+func (r *markdownRenderer) block(n ADFNode) []string {
+	switch n.Type {
+	case "paragraph":
+		return r.paragraph(n.Content)
+
+	case "heading":
+		level := attrInt(n.Attrs, "level", 1)
+		text := strings.ReplaceAll(r.inline(n.Content), "\n", " ")
+		return []string{strings.Repeat("#", level) + " " + text}
+
+	case "bulletList":
+		var lines []string
+		for _, item := range n.Content {
+			lines = append(lines, r.listItem(item, "- ")...)
+		}
+		return lines
+
+	case "orderedList":
+		start := attrInt(n.Attrs, "order", 1)
+		var lines []string
+		for i, item := range n.Content {
+			lines = append(lines, r.listItem(item, strconv.Itoa(start+i)+". ")...)
+		}
+		return lines
+
+	case "taskList":
+		var lines []string
+		for _, item := range n.Content {
+			if item.Type == "taskList" {
+				lines = append(lines, indentLines(r.block(item), "  ")...)
+				continue
+			}
+			box := "- [ ] "
+			if attrString(item.Attrs, "state") == "DONE" {
+				box = "- [x] "
+			}
+			lines = append(lines, prefixLines(r.paragraph(item.Content), box, "      ")...)
+		}
+		return lines
+
+	case "decisionList":
+		var lines []string
+		for _, item := range n.Content {
+			lines = append(lines, prefixLines(r.paragraph(item.Content), "- ", "  ")...)
+		}
+		return lines
+
+	case "codeBlock":
+		code := strings.TrimRight(plainText(n), "\n")
+		fence := "```"
+		for strings.Contains(code, fence) {
+			fence += "`"
+		}
+		lines := []string{fence + attrString(n.Attrs, "language")}
+		lines = append(lines, strings.Split(code, "\n")...)
+		return append(lines, fence)
+
+	case "blockquote":
+		return prefixLines(r.blocks(n.Content), "> ", "> ")
+
+	case "rule":
+		return []string{"---"}
+
+	case "panel":
+		label := attrString(n.Attrs, "panelType")
+		if label == "" {
+			label = "info"
+		}
+		lines := []string{"**" + strings.ToUpper(label[:1]) + label[1:] + ":**", ""}
+		lines = append(lines, r.blocks(n.Content)...)
+		return prefixLines(lines, "> ", "> ")
+
+	case "expand", "nestedExpand":
+		title := attrString(n.Attrs, "title")
+		if title == "" {
+			title = "Details"
+		}
+		lines := []string{"**" + markdownEscaper.Replace(title) + "**", ""}
+		return append(lines, r.blocks(n.Content)...)
+
+	case "table":
+		return r.table(n)
+
+	case "mediaSingle", "mediaGroup":
+		var lines []string
+		for _, c := range n.Content {
+			lines = append(lines, r.block(c)...)
+		}
+		return lines
+
+	case "media":
+		name := attrString(n.Attrs, "alt")
+		if name == "" {
+			name = attrString(n.Attrs, "id")
+		}
+		return []string{"*[attachment: " + markdownEscaper.Replace(name) + "]*"}
+
+	case "blockCard", "embedCard":
+		return []string{"<" + attrString(n.Attrs, "url") + ">"}
+	}
+
+	if len(n.Content) > 0 && isInline(n.Content[0]) {
+		return r.paragraph(n.Content)
+	}
+	if n.Text != "" {
+		return r.paragraph([]ADFNode{n})
+	}
+	return r.blocks(n.Content)
+}
+
+// paragraph renders inline content, escaping anything that would be read as block syntax
+func (r *markdownRenderer) paragraph(nodes []ADFNode) []string {
+	lines := strings.Split(r.inline(nodes), "\n")
+	for i, line := range lines {
+		// Escape the punctuation itself, so "1." becomes "1\." rather than "\1."
+		if m := markdownLineStart.FindStringSubmatchIndex(line); m != nil {
+			at := m[5] - 1
+			lines[i] = line[:at] + `\` + line[at:]
+		}
+		if i < len(lines)-1 {
+			lines[i] += `\`
+		}
+	}
+	return lines
+}
+
+func (r *markdownRenderer) listItem(item ADFNode, marker string) []string {
+	lines := r.tightBlocks(item.Content)
+	if len(lines) == 0 {
+		lines = []string{""}
+	}
+	return prefixLines(lines, marker, strings.Repeat(" ", len(marker)))
+}
+
+// table renders a GFM pipe table; the first row always becomes the header
+func (r *markdownRenderer) table(n ADFNode) []string {
+	var rows [][]string
+	cols := 0
+	for _, row := range n.Content {
+		var cells []string
+		for _, c := range row.Content {
+			text := strings.Join(r.tightBlocks(c.Content), " ")
+			cells = append(cells, strings.ReplaceAll(text, "|", `\|`))
+		}
+		if len(cells) > cols {
+			cols = len(cells)
+		}
+		rows = append(rows, cells)
+	}
+	if cols == 0 {
+		return nil
+	}
+
+	format := func(cells []string) string {
+		for len(cells) < cols {
+			cells = append(cells, "")
+		}
+		return "| " + strings.Join(cells, " | ") + " |"
+	}
+
+	lines := []string{format(rows[0])}
+	sep := make([]string, cols)
+	for i := range sep {
+		sep[i] = "---"
+	}
+	lines = append(lines, format(sep))
+	for _, row := range rows[1:] {
+		lines = append(lines, format(row))
+	}
+	return lines
+}
+
+func (r *markdownRenderer) inline(nodes []ADFNode) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		switch n.Type {
+		case "text":
+			b.WriteString(markdownText(n))
+		case "hardBreak":
+			b.WriteString("\n")
+		case "mention":
+			fmt.Fprintf(&b, "[@%s](accountid:%s)", markdownEscaper.Replace(mentionName(n.Attrs)), attrString(n.Attrs, "id"))
+		case "emoji", "date":
+			b.WriteString(plainText(n))
+		case "status":
+			b.WriteString("**\\[" + markdownEscaper.Replace(strings.ToUpper(attrString(n.Attrs, "text"))) + "\\]**")
+		case "inlineCard":
+			b.WriteString("<" + attrString(n.Attrs, "url") + ">")
+		default:
+			if len(n.Content) > 0 {
+				b.WriteString(r.inline(n.Content))
+			} else {
+				b.WriteString(markdownEscaper.Replace(n.Text))
+			}
+		}
+	}
+	return b.String()
+}
+
+// markdownText wraps a text node in the Markdown syntax for its marks
+func markdownText(n ADFNode) string {
+	var code, strong, em, strike bool
+	href := ""
+	for _, m := range n.Marks {
+		switch m.Type {
+		case "code":
+			code = true
+		case "strong":
+			strong = true
+		case "em":
+			em = true
+		case "strike":
+			strike = true
+		case "link":
+			href = attrString(m.Attrs, "href")
+		}
+	}
+
+	text := n.Text
+	if code {
+		fence := "`"
+		for strings.Contains(text, fence) {
+			fence += "`"
+		}
+		if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+			text = " " + text + " "
+		}
+		text = fence + text + fence
+	} else {
+		text = markdownEscaper.Replace(text)
+	}
+
+	// Emphasis delimiters must hug the text, so keep surrounding spaces outside them
+	lead, trail := "", ""
+	if !code && (em || strong || strike) {
+		core := strings.TrimSpace(text)
+		if core == "" {
+			return text
+		}
+		lead = text[:strings.Index(text, core)]
+		trail = text[len(lead)+len(core):]
+		text = core
+	}
+
+	if em {
+		text = "*" + text + "*"
+	}
+	if strong {
+		text = "**" + text + "**"
+	}
+	if strike {
+		text = "~~" + text + "~~"
+	}
+	text = lead + text + trail
+	if href != "" {
+		text = "[" + text + "](" + href + ")"
+	}
+	return text
+}
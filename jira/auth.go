@@ -0,0 +1,128 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Authenticator adds credentials to a request
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// BasicAuth authenticates with an email and API token on Cloud, or a username and
+// password on Server and Data Center; without either the request is anonymous
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+func (a BasicAuth) Authenticate(req *http.Request) error {
+	if a.Username != "" || a.Password != "" {
+		req.SetBasicAuth(a.Username, a.Password)
+	}
+	return nil
+}
+
+// BearerAuth authenticates with a personal access token (Data Center 8.14 and later)
+type BearerAuth struct {
+	Token string
+}
+
+func (a BearerAuth) Authenticate(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+a.Token)
+	return nil
+}
+
+// Flavor is the kind of Jira deployment a client talks to
+type Flavor string
+
+const (
+	// Cloud speaks REST API v3 with ADF rich text and account IDs
+	Cloud Flavor = "cloud"
+	// Server is Jira Server or Data Center: REST API v2, wiki markup and usernames
+	Server Flavor = "server"
+)
+
+type ServerInfo struct {
+	BaseURL        string `json:"baseUrl"`
+	Version        string `json:"version"`
+	DeploymentType string `json:"deploymentType"`
+	ServerTitle    string `json:"serverTitle"`
+}
+
+// GetServerInfo returns the version and deployment type of the Jira instance
+func (c *Client) GetServerInfo(ctx context.Context) (*ServerInfo, error) {
+	u, err := c.baseURL.Parse("serverInfo")
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.getCached(ctx, u.String(), "", metadataTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	var info ServerInfo
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// Flavor returns whether the client talks to Cloud or Server, asking serverInfo unless
+// it was configured
+func (c *Client) Flavor(ctx context.Context) (Flavor, error) {
+	if c.flavor != "" {
+		return c.flavor, nil
+	}
+
+	info, err := c.GetServerInfo(ctx)
+	if err != nil {
+		return "", err
+	}
+	c.flavor = Server
+	if strings.EqualFold(info.DeploymentType, "Cloud") {
+		c.flavor = Cloud
+	}
+	return c.flavor, nil
+}
+
+// isServer reports whether the client talks to Server or Data Center; if that can't be
+// determined it assumes Cloud
+func (c *Client) isServer(ctx context.Context) bool {
+	flavor, _ := c.Flavor(ctx)
+	return flavor == Server
+}
+
+// richText converts rich text to what the instance accepts: ADF on Cloud, wiki markup on Server
+func (c *Client) richText(ctx context.Context, doc *ADFNode) any {
+	if doc != nil && c.isServer(ctx) {
+		return ADFToWiki(doc)
+	}
+	return doc
+}
+
+// instanceFields converts the values of a fields map for the instance: ADF as richText
+// does, and users to account IDs or usernames, see UserValue
+func (c *Client) instanceFields(ctx context.Context, fields map[string]any) map[string]any {
+	converted := make(map[string]any, len(fields))
+	for id, value := range fields {
+		switch v := value.(type) {
+		case *ADFNode:
+			value = c.richText(ctx, v)
+		case UserValue:
+			value = c.userField(ctx, string(v))
+		case []UserValue:
+			users := make([]map[string]any, len(v))
+			for i, user := range v {
+				users[i] = c.userField(ctx, string(user))
+			}
+			value = users
+		}
+		converted[id] = value
+	}
+	return converted
+}
@@ -0,0 +1,93 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// GetBacklog returns the issues in a board's backlog in rank order, fetching only the given fields
+// (everything when none are given)
+func (c *Client) GetBacklog(ctx context.Context, boardID int, fields ...string) ([]Issue, error) {
+	var issues []Issue
+	for {
+		query := url.Values{}
+		query.Set("startAt", fmt.Sprint(len(issues)))
+		query.Set("maxResults", "100")
+		if len(fields) > 0 {
+			query.Set("fields", strings.Join(fields, ","))
+		}
+
+		u, err := c.agileURL.Parse(fmt.Sprintf("board/%d/backlog?%s", boardID, query.Encode()))
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := c.makeRequest(ctx, "GET", u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page IssuesResponse
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, err
+		}
+
+		issues = append(issues, page.Issues...)
+		if len(page.Issues) == 0 || len(issues) >= page.Total {
+			return issues, nil
+		}
+	}
+}
+
+// RankIssues moves issues directly above (before) or below another issue in the board's rank order
+func (c *Client) RankIssues(ctx context.Context, issueKeys []string, otherKey string, before bool) error {
+	payload := map[string]any{
+		"issues": issueKeys,
+	}
+	if before {
+		payload["rankBeforeIssue"] = otherKey
+	} else {
+		payload["rankAfterIssue"] = otherKey
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	u, err := c.agileURL.Parse("issue/rank")
+	if err != nil {
+		return err
+	}
+
+	_, err = c.makeRequest(ctx, "PUT", u.String(), bytes.NewReader(jsonData))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetEstimate sets an issue's estimate in whatever field the board estimates with (usually story points)
+func (c *Client) SetEstimate(ctx context.Context, boardID int, issueKey, value string) error {
+	jsonData, err := json.Marshal(map[string]any{"value": value})
+	if err != nil {
+		return err
+	}
+
+	u, err := c.agileURL.Parse(fmt.Sprintf("issue/%s/estimation?boardId=%d", issueKey, boardID))
+	if err != nil {
+		return err
+	}
+
+	_, err = c.makeRequest(ctx, "PUT", u.String(), bytes.NewReader(jsonData))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// BoardColumn is a column on a board and the statuses mapped to it
+type BoardColumn struct {
+	Name     string `json:"name"`
+	Statuses []struct {
+		ID string `json:"id"`
+	} `json:"statuses"`
+}
+
+type BoardConfiguration struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	ColumnConfig struct {
+		Columns []BoardColumn `json:"columns"`
+	} `json:"columnConfig"`
+}
+
+// HasStatus reports whether a status ID is mapped to the column
+func (c BoardColumn) HasStatus(statusID string) bool {
+	for _, status := range c.Statuses {
+		if status.ID == statusID {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Client) GetBoardConfiguration(ctx context.Context, boardID int) (*BoardConfiguration, error) {
+	u, err := c.agileURL.Parse(fmt.Sprintf("board/%d/configuration", boardID))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.makeRequest(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var config BoardConfiguration
+	if err := json.Unmarshal(body, &config); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// GetBoardIssues returns the issues on a board matching jql, for boards without sprints
+func (c *Client) GetBoardIssues(ctx context.Context, boardID int, jql string) ([]Issue, error) {
+	var issues []Issue
+	for {
+		query := url.Values{}
+		query.Set("startAt", fmt.Sprint(len(issues)))
+		query.Set("maxResults", "100")
+		if jql != "" {
+			query.Set("jql", jql)
+		}
+
+		u, err := c.agileURL.Parse(fmt.Sprintf("board/%d/issue?%s", boardID, query.Encode()))
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := c.makeRequest(ctx, "GET", u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page IssuesResponse
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, err
+		}
+
+		issues = append(issues, page.Issues...)
+		if len(page.Issues) == 0 || len(issues) >= page.Total {
+			return issues, nil
+		}
+	}
+}
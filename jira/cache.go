@@ -0,0 +1,133 @@
+package jira
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// How long rarely changing lookups are reused before asking Jira again
+const (
+	userTTL     = 7 * 24 * time.Hour
+	metadataTTL = 24 * time.Hour
+)
+
+type cacheEntry struct {
+	body   []byte
+	stored time.Time
+}
+
+// responseCache keeps GET responses in memory for the session and, when dir is set, on disk
+// across runs; entries expire after the TTL given when they are read
+type responseCache struct {
+	dir     string
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+func (rc *responseCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(rc.dir, hex.EncodeToString(sum[:16])+".json")
+}
+
+func (rc *responseCache) get(key string, ttl time.Duration) ([]byte, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if entry, ok := rc.entries[key]; ok && time.Since(entry.stored) < ttl {
+		return entry.body, true
+	}
+	if rc.dir == "" {
+		return nil, false
+	}
+
+	path := rc.path(key)
+	stat, err := os.Stat(path)
+	if err != nil || time.Since(stat.ModTime()) >= ttl {
+		return nil, false
+	}
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	rc.entries[key] = cacheEntry{body: body, stored: stat.ModTime()}
+	return body, true
+}
+
+func (rc *responseCache) set(key string, body []byte) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.entries[key] = cacheEntry{body: body, stored: time.Now()}
+	if rc.dir == "" {
+		return
+	}
+	// The disk cache is best effort; a failed write only costs a request next time
+	if err := os.MkdirAll(rc.dir, 0700); err == nil {
+		os.WriteFile(rc.path(key), body, 0600)
+	}
+}
+
+// getCached is makeRequest for GETs of data that rarely changes. key identifies the entry and
+// defaults to the URL; it is scoped to the site (OAuth sites share a host) and to the
+// authenticated user, since responses such as /myself depend on who asks
+func (c *Client) getCached(ctx context.Context, urlStr, key string, ttl time.Duration) ([]byte, error) {
+	if key == "" {
+		key = urlStr
+	}
+	scope, err := c.CacheScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	key = scope + "\n" + key
+
+	if body, ok := c.cache.get(key, ttl); ok {
+		return body, nil
+	}
+
+	body, err := c.makeRequest(ctx, "GET", urlStr, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.set(key, body)
+	return body, nil
+}
+
+// CacheScope identifies the site and the user the client works as, for callers that keep
+// their own caches of per-user data
+func (c *Client) CacheScope(ctx context.Context) (string, error) {
+	identity, err := c.cacheIdentity(ctx)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(identity + "\n" + c.baseURL.String()))
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+// cacheIdentity names who the client authenticates as, so accounts sharing a cache directory
+// never see each other's responses. Tokens are hashed rather than kept in the key; OAuth
+// tokens rotate, so OAuth clients are identified by their account ID, asked once per session
+func (c *Client) cacheIdentity(ctx context.Context) (string, error) {
+	switch auth := c.auth.(type) {
+	case BasicAuth:
+		return "basic:" + auth.Username, nil
+	case BearerAuth:
+		sum := sha256.Sum256([]byte(auth.Token))
+		return "bearer:" + hex.EncodeToString(sum[:8]), nil
+	}
+
+	c.identityMu.Lock()
+	defer c.identityMu.Unlock()
+	if c.identity == "" {
+		user, err := c.Myself(ctx)
+		if err != nil {
+			return "", err
+		}
+		c.identity = "account:" + user.ID()
+	}
+	return c.identity, nil
+}
@@ -0,0 +1,114 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// Field is an entry of the instance-wide field list
+type Field struct {
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	Custom      bool        `json:"custom"`
+	ClauseNames []string    `json:"clauseNames"`
+	Schema      FieldSchema `json:"schema"`
+}
+
+// commonFieldNames lists well known custom fields by the names Jira gives them,
+// keyed by the friendly name jig uses in config.toml
+var commonFieldNames = map[string][]string{
+	"story_points":        {"Story Points", "Story point estimate"},
+	"epic_link":           {"Epic Link"},
+	"epic_name":           {"Epic Name"},
+	"sprint":              {"Sprint"},
+	"team":                {"Team"},
+	"acceptance_criteria": {"Acceptance Criteria"},
+	"start_date":          {"Start date"},
+}
+
+func (c *Client) GetFields(ctx context.Context) ([]Field, error) {
+	u, err := c.baseURL.Parse("field")
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.getCached(ctx, u.String(), "", metadataTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []Field
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+// DetectCommonFields maps friendly names like story_points to the custom field IDs of this instance
+func DetectCommonFields(fields []Field) map[string]string {
+	detected := map[string]string{}
+	for friendly, names := range commonFieldNames {
+		// Earlier names in the list take priority, e.g. classic "Story Points" over the team-managed estimate
+		for _, name := range names {
+			if id := findCustomField(fields, name); id != "" {
+				detected[friendly] = id
+				break
+			}
+		}
+	}
+	return detected
+}
+
+func findCustomField(fields []Field, name string) string {
+	for _, field := range fields {
+		if field.Custom && strings.EqualFold(field.Name, name) {
+			return field.ID
+		}
+	}
+	return ""
+}
+
+// customFields picks the customfield_* entries out of a raw fields object
+func customFields(raw map[string]any) map[string]any {
+	custom := map[string]any{}
+	for id, value := range raw {
+		if strings.HasPrefix(id, "customfield_") && value != nil {
+			custom[id] = value
+		}
+	}
+	return custom
+}
+
+func (i *Issue) UnmarshalJSON(data []byte) error {
+	type plain Issue
+	if err := json.Unmarshal(data, (*plain)(i)); err != nil {
+		return err
+	}
+
+	var raw struct {
+		Fields map[string]any `json:"fields"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	i.CustomFields = customFields(raw.Fields)
+	return nil
+}
+
+func (i *DetailedIssue) UnmarshalJSON(data []byte) error {
+	type plain DetailedIssue
+	if err := json.Unmarshal(data, (*plain)(i)); err != nil {
+		return err
+	}
+
+	var raw struct {
+		Fields map[string]any `json:"fields"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	i.CustomFields = customFields(raw.Fields)
+	return nil
+}
@@ -8,7 +8,9 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -17,14 +19,27 @@ type Config struct {
 	AgileURL string
 	Email    string
 	APIKey   string
+	// Auth overrides basic authentication with Email and APIKey, e.g. with a BearerAuth
+	Auth Authenticator
+	// Flavor is Cloud or Server; when empty it is detected from serverInfo
+	Flavor Flavor
+	// CacheDir keeps metadata lookups across runs; when empty they are only cached in memory
+	CacheDir string
 }
 
 type Client struct {
 	baseURL    *url.URL
 	agileURL   *url.URL
-	email      string
-	apiKey     string
+	auth       Authenticator
+	flavor     Flavor
 	httpClient *http.Client
+	// transferClient has no overall timeout so large uploads and downloads can finish
+	transferClient *http.Client
+	cache          *responseCache
+
+	// identity scopes cache entries to the authenticated user, see cacheIdentity
+	identityMu sync.Mutex
+	identity   string
 }
 
 // creates a new Jira API client
@@ -46,24 +61,46 @@ func NewClient(cfg Config) (*Client, error) {
 		agile.Path += "/"
 	}
 
+	auth := cfg.Auth
+	if auth == nil {
+		auth = BasicAuth{Username: cfg.Email, Password: cfg.APIKey}
+	}
+
 	return &Client{
-		baseURL:    base,
-		agileURL:   agile,
-		email:      cfg.Email,
-		apiKey:     cfg.APIKey,
+		baseURL:  base,
+		agileURL: agile,
+		auth:     auth,
+		flavor:   cfg.Flavor,
 		httpClient: &http.Client{
 			Timeout: time.Second * 10,
 		},
+		transferClient: &http.Client{},
+		cache: &responseCache{
+			dir:     cfg.CacheDir,
+			entries: map[string]cacheEntry{},
+		},
 	}, nil
 }
 
-func (c *Client) makeRequest(ctx context.Context, method, urlStr string, body io.Reader) ([]byte, error) {
+// newRequest creates an authenticated request
+func (c *Client) newRequest(ctx context.Context, method, urlStr string, body io.Reader) (*http.Request, error) {
 	req, err := http.NewRequestWithContext(ctx, method, urlStr, body)
 	if err != nil {
 		return nil, err
 	}
 
-	req.SetBasicAuth(c.email, c.apiKey)
+	if err := c.auth.Authenticate(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func (c *Client) makeRequest(ctx context.Context, method, urlStr string, body io.Reader) ([]byte, error) {
+	req, err := c.newRequest(ctx, method, urlStr, body)
+	if err != nil {
+		return nil, err
+	}
+
 	req.Header.Set("Accept", "application/json")
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
@@ -106,8 +143,16 @@ func (c *Client) GetSprints(ctx context.Context, boardID int) ([]Sprint, error)
 	return sprintsResp.Values, nil
 }
 
-func (c *Client) GetSprintIssues(ctx context.Context, sprintID int) ([]Issue, error) {
-	jql := url.QueryEscape("type in (Story, Task, Bug)")
+// GetSprintIssues returns a sprint's issues of the given types, by default stories, tasks and bugs
+func (c *Client) GetSprintIssues(ctx context.Context, sprintID int, issueTypes ...string) ([]Issue, error) {
+	if len(issueTypes) == 0 {
+		issueTypes = []string{"Story", "Task", "Bug"}
+	}
+	quoted := make([]string, len(issueTypes))
+	for i, issueType := range issueTypes {
+		quoted[i] = strconv.Quote(issueType)
+	}
+	jql := url.QueryEscape("type in (" + strings.Join(quoted, ", ") + ")")
 	u, err := c.agileURL.Parse(fmt.Sprintf("sprint/%d/issue?jql=%s", sprintID, jql))
 	if err != nil {
 		return nil, err
@@ -126,6 +171,32 @@ func (c *Client) GetSprintIssues(ctx context.Context, sprintID int) ([]Issue, er
 	return issuesResp.Issues, nil
 }
 
+// GetAllSprintIssues returns every issue in a sprint, whatever its type
+func (c *Client) GetAllSprintIssues(ctx context.Context, sprintID int) ([]Issue, error) {
+	var issues []Issue
+	for {
+		u, err := c.agileURL.Parse(fmt.Sprintf("sprint/%d/issue?maxResults=100&startAt=%d", sprintID, len(issues)))
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := c.makeRequest(ctx, "GET", u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page IssuesResponse
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, err
+		}
+
+		issues = append(issues, page.Issues...)
+		if len(page.Issues) == 0 || len(issues) >= page.Total {
+			return issues, nil
+		}
+	}
+}
+
 func (c *Client) getSubtaskIssueTypeID(ctx context.Context, parentKey string) (string, error) {
 	projectKey := parentKey[:strings.Index(parentKey, "-")]
 
@@ -134,7 +205,7 @@ func (c *Client) getSubtaskIssueTypeID(ctx context.Context, parentKey string) (s
 		return "", err
 	}
 
-	body, err := c.makeRequest(ctx, "GET", u.String(), nil)
+	body, err := c.getCached(ctx, u.String(), "", metadataTTL)
 	if err != nil {
 		return "", err
 	}
@@ -167,7 +238,8 @@ func (c *Client) getSubtaskIssueTypeID(ctx context.Context, parentKey string) (s
 	return "", fmt.Errorf("no subtask issue type found in project")
 }
 
-func (c *Client) CreateSubtask(ctx context.Context, parentKey, summary string) (string, error) {
+// CreateSubtask creates a subtask under parentKey; description may be nil
+func (c *Client) CreateSubtask(ctx context.Context, parentKey, summary string, description *ADFNode) (string, error) {
 	projectKey := parentKey[:strings.Index(parentKey, "-")]
 
 	subtaskTypeID, err := c.getSubtaskIssueTypeID(ctx, parentKey)
@@ -175,19 +247,29 @@ func (c *Client) CreateSubtask(ctx context.Context, parentKey, summary string) (
 		return "", fmt.Errorf("failed to get subtask issue type: %v", err)
 	}
 
-	payload := map[string]any{
-		"fields": map[string]any{
-			"project": map[string]any{
-				"key": projectKey,
-			},
-			"parent": map[string]any{
-				"key": parentKey,
-			},
-			"summary": summary,
-			"issuetype": map[string]any{
-				"id": subtaskTypeID,
-			},
+	fields := map[string]any{
+		"project": map[string]any{
+			"key": projectKey,
+		},
+		"parent": map[string]any{
+			"key": parentKey,
 		},
+		"summary": summary,
+		"issuetype": map[string]any{
+			"id": subtaskTypeID,
+		},
+	}
+	if description != nil {
+		fields["description"] = description
+	}
+
+	return c.CreateIssue(ctx, fields)
+}
+
+// CreateIssue creates an issue from a raw fields map and returns its key
+func (c *Client) CreateIssue(ctx context.Context, fields map[string]any) (string, error) {
+	payload := map[string]any{
+		"fields": c.instanceFields(ctx, fields),
 	}
 
 	jsonData, err := json.Marshal(payload)
@@ -218,38 +300,50 @@ func (c *Client) CreateSubtask(ctx context.Context, parentKey, summary string) (
 	return key, nil
 }
 
-func (c *Client) getCurrentUser(ctx context.Context) (string, error) {
-	u, err := c.baseURL.Parse("myself")
+// GetProjectIssueTypes returns the issue types that can be created in a project
+func (c *Client) GetProjectIssueTypes(ctx context.Context, projectKey string) ([]IssueType, error) {
+	u, err := c.baseURL.Parse(fmt.Sprintf("issue/createmeta/%s/issuetypes", projectKey))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	body, err := c.makeRequest(ctx, "GET", u.String(), nil)
+	body, err := c.getCached(ctx, u.String(), "", metadataTTL)
 	if err != nil {
-		return "", err
-	}
-
-	var user map[string]any
-	if err := json.Unmarshal(body, &user); err != nil {
-		return "", err
+		return nil, err
 	}
 
-	accountId, ok := user["accountId"].(string)
-	if !ok {
-		return "", fmt.Errorf("failed to get accountId from user response")
+	var typesResp IssueTypesResponse
+	if err := json.Unmarshal(body, &typesResp); err != nil {
+		return nil, err
 	}
 
-	return accountId, nil
+	return append(typesResp.IssueTypes, typesResp.Values...), nil
 }
 
-func (c *Client) AssignToSelf(ctx context.Context, issueKey string) error {
-	accountId, err := c.getCurrentUser(ctx)
+// GetCreateFields returns the create-metadata fields for an issue type in a project
+func (c *Client) GetCreateFields(ctx context.Context, projectKey, issueTypeID string) ([]FieldMeta, error) {
+	u, err := c.baseURL.Parse(fmt.Sprintf("issue/createmeta/%s/issuetypes/%s?maxResults=200", projectKey, issueTypeID))
 	if err != nil {
-		return fmt.Errorf("failed to get current user: %v", err)
+		return nil, err
 	}
 
+	body, err := c.getCached(ctx, u.String(), "", metadataTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	var fieldsResp CreateMetaFieldsResponse
+	if err := json.Unmarshal(body, &fieldsResp); err != nil {
+		return nil, err
+	}
+
+	return append(fieldsResp.Fields, fieldsResp.Values...), nil
+}
+
+// MoveIssuesToSprint adds issues to a sprint
+func (c *Client) MoveIssuesToSprint(ctx context.Context, sprintID int, issueKeys []string) error {
 	payload := map[string]any{
-		"accountId": accountId,
+		"issues": issueKeys,
 	}
 
 	jsonData, err := json.Marshal(payload)
@@ -257,12 +351,12 @@ func (c *Client) AssignToSelf(ctx context.Context, issueKey string) error {
 		return err
 	}
 
-	u, err := c.baseURL.Parse(fmt.Sprintf("issue/%s/assignee", issueKey))
+	u, err := c.agileURL.Parse(fmt.Sprintf("sprint/%d/issue", sprintID))
 	if err != nil {
 		return err
 	}
 
-	_, err = c.makeRequest(ctx, "PUT", u.String(), bytes.NewReader(jsonData))
+	_, err = c.makeRequest(ctx, "POST", u.String(), bytes.NewReader(jsonData))
 	if err != nil {
 		return err
 	}
@@ -270,6 +364,38 @@ func (c *Client) AssignToSelf(ctx context.Context, issueKey string) error {
 	return nil
 }
 
+func (c *Client) getCurrentUser(ctx context.Context) (string, error) {
+	u, err := c.baseURL.Parse("myself")
+	if err != nil {
+		return "", err
+	}
+
+	body, err := c.getCached(ctx, u.String(), "", userTTL)
+	if err != nil {
+		return "", err
+	}
+
+	var user User
+	if err := json.Unmarshal(body, &user); err != nil {
+		return "", err
+	}
+
+	if user.ID() == "" {
+		return "", fmt.Errorf("failed to get accountId from user response")
+	}
+
+	return user.ID(), nil
+}
+
+func (c *Client) AssignToSelf(ctx context.Context, issueKey string) error {
+	accountId, err := c.getCurrentUser(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get current user: %v", err)
+	}
+
+	return c.AssignIssue(ctx, issueKey, accountId)
+}
+
 func (c *Client) GetTransitions(ctx context.Context, issueKey string) ([]Transition, error) {
 	u, err := c.baseURL.Parse(fmt.Sprintf("issue/%s/transitions", issueKey))
 	if err != nil {
@@ -281,6 +407,32 @@ func (c *Client) GetTransitions(ctx context.Context, issueKey string) ([]Transit
 		return nil, err
 	}
 
+	return parseTransitions(body)
+}
+
+// GetWorkflowTransitions is GetTransitions cached per project, issue type and status, which
+// together determine the workflow step an issue is in
+func (c *Client) GetWorkflowTransitions(ctx context.Context, issueKey, issueType, statusID string) ([]Transition, error) {
+	if issueType == "" || statusID == "" {
+		return c.GetTransitions(ctx, issueKey)
+	}
+
+	u, err := c.baseURL.Parse(fmt.Sprintf("issue/%s/transitions", issueKey))
+	if err != nil {
+		return nil, err
+	}
+
+	project, _, _ := strings.Cut(issueKey, "-")
+	key := fmt.Sprintf("transitions/%s/%s/%s", project, issueType, statusID)
+	body, err := c.getCached(ctx, u.String(), key, metadataTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseTransitions(body)
+}
+
+func parseTransitions(body []byte) ([]Transition, error) {
 	var transitionsResp TransitionsResponse
 	if err := json.Unmarshal(body, &transitionsResp); err != nil {
 		return nil, err
@@ -370,3 +522,104 @@ func (c *Client) GetProjectBoards(ctx context.Context, projectKeyOrID string) ([
 
 	return boardsResp.Values, nil
 }
+
+// AddComment posts an ADF comment to an issue
+func (c *Client) AddComment(ctx context.Context, issueKey string, body *ADFNode) error {
+	payload := map[string]any{
+		"body": c.richText(ctx, body),
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	u, err := c.baseURL.Parse(fmt.Sprintf("issue/%s/comment", issueKey))
+	if err != nil {
+		return err
+	}
+
+	_, err = c.makeRequest(ctx, "POST", u.String(), bytes.NewReader(jsonData))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetDescription replaces the description of an issue
+func (c *Client) SetDescription(ctx context.Context, issueKey string, description *ADFNode) error {
+	return c.UpdateIssue(ctx, issueKey, IssueUpdate{
+		Fields: map[string]any{"description": description},
+	})
+}
+
+// UpdateIssue edits the fields of an issue
+func (c *Client) UpdateIssue(ctx context.Context, issueKey string, update IssueUpdate) error {
+	update.Fields = c.instanceFields(ctx, update.Fields)
+	jsonData, err := json.Marshal(update)
+	if err != nil {
+		return err
+	}
+
+	u, err := c.baseURL.Parse(fmt.Sprintf("issue/%s", issueKey))
+	if err != nil {
+		return err
+	}
+
+	_, err = c.makeRequest(ctx, "PUT", u.String(), bytes.NewReader(jsonData))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetEditMeta returns the fields that can be edited on an issue, keyed by field ID
+func (c *Client) GetEditMeta(ctx context.Context, issueKey string) (map[string]FieldMeta, error) {
+	u, err := c.baseURL.Parse(fmt.Sprintf("issue/%s/editmeta", issueKey))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.makeRequest(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var metaResp EditMetaResponse
+	if err := json.Unmarshal(body, &metaResp); err != nil {
+		return nil, err
+	}
+
+	for id, field := range metaResp.Fields {
+		if field.Key == "" {
+			field.Key = id
+			metaResp.Fields[id] = field
+		}
+	}
+
+	return metaResp.Fields, nil
+}
+
+// GetIssueFields returns the raw values of the requested fields of an issue
+func (c *Client) GetIssueFields(ctx context.Context, issueKey string, fields ...string) (map[string]any, error) {
+	u, err := c.baseURL.Parse(fmt.Sprintf("issue/%s?fields=%s", issueKey, url.QueryEscape(strings.Join(fields, ","))))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.makeRequest(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var issue struct {
+		Fields map[string]any `json:"fields"`
+	}
+	if err := json.Unmarshal(body, &issue); err != nil {
+		return nil, err
+	}
+
+	return issue.Fields, nil
+}
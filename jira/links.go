@@ -0,0 +1,119 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type IssueLinkType struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Inward  string `json:"inward"`
+	Outward string `json:"outward"`
+}
+
+type IssueLinkTypesResponse struct {
+	IssueLinkTypes []IssueLinkType `json:"issueLinkTypes"`
+}
+
+// LinkedIssue is the abbreviated issue embedded in an issue link
+type LinkedIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary string `json:"summary"`
+		Status  Status `json:"status"`
+	} `json:"fields"`
+}
+
+// IssueLink is one entry of an issue's issuelinks field; exactly one of
+// InwardIssue and OutwardIssue is set, naming the other end of the link
+type IssueLink struct {
+	ID           string        `json:"id"`
+	Type         IssueLinkType `json:"type"`
+	InwardIssue  *LinkedIssue  `json:"inwardIssue,omitempty"`
+	OutwardIssue *LinkedIssue  `json:"outwardIssue,omitempty"`
+}
+
+// Other returns the linked issue and the phrase describing this issue's relation to it,
+// e.g. "blocks" or "is blocked by"
+func (l IssueLink) Other() (*LinkedIssue, string) {
+	if l.OutwardIssue != nil {
+		return l.OutwardIssue, l.Type.Outward
+	}
+	return l.InwardIssue, l.Type.Inward
+}
+
+// IsBlockedBy reports whether the link says this issue is blocked by an issue that isn't done
+func (l IssueLink) IsBlockedBy() bool {
+	if l.InwardIssue == nil {
+		return false
+	}
+	if !strings.EqualFold(l.Type.Name, "Blocks") && !strings.Contains(strings.ToLower(l.Type.Inward), "blocked by") {
+		return false
+	}
+	return l.InwardIssue.Fields.Status.StatusCategory.Key != "done"
+}
+
+func (c *Client) GetIssueLinkTypes(ctx context.Context) ([]IssueLinkType, error) {
+	u, err := c.baseURL.Parse("issueLinkType")
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.getCached(ctx, u.String(), "", metadataTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	var typesResp IssueLinkTypesResponse
+	if err := json.Unmarshal(body, &typesResp); err != nil {
+		return nil, err
+	}
+
+	return typesResp.IssueLinkTypes, nil
+}
+
+// CreateIssueLink links two issues so that "from <linkType.Outward> to" holds,
+// e.g. from blocks to
+func (c *Client) CreateIssueLink(ctx context.Context, linkType, from, to string) error {
+	// Jira reads the link as "inwardIssue <outward> outwardIssue"
+	payload := map[string]any{
+		"type":         map[string]any{"name": linkType},
+		"inwardIssue":  map[string]any{"key": from},
+		"outwardIssue": map[string]any{"key": to},
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	u, err := c.baseURL.Parse("issueLink")
+	if err != nil {
+		return err
+	}
+
+	_, err = c.makeRequest(ctx, "POST", u.String(), bytes.NewReader(jsonData))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Client) DeleteIssueLink(ctx context.Context, linkID string) error {
+	u, err := c.baseURL.Parse(fmt.Sprintf("issueLink/%s", linkID))
+	if err != nil {
+		return err
+	}
+
+	_, err = c.makeRequest(ctx, "DELETE", u.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
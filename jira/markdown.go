@@ -0,0 +1,646 @@
+package jira
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	mdHeading     = regexp.MustCompile(`^(#{1,6})(?:\s+(.*?))?\s*#*\s*$`)
+	mdFence       = regexp.MustCompile("^(`{3,}|~{3,})\\s*([^`\\s]*)")
+	mdRule        = regexp.MustCompile(`^ {0,3}(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+	mdListItem    = regexp.MustCompile(`^( *)([-*+]|\d{1,9}[.)])( +|$)`)
+	mdTaskBox     = regexp.MustCompile(`^\[([ xX])\] `)
+	mdTableSep    = regexp.MustCompile(`^\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+	mdPanelHeader = regexp.MustCompile(`^\*\*(Info|Note|Warning|Success|Error):\*\*$`)
+)
+
+// MarkdownToADF converts a Markdown document into an ADF document suitable for
+// descriptions, comments and worklog notes
+func MarkdownToADF(md string) *ADFNode {
+	md = strings.ReplaceAll(md, "\r\n", "\n")
+	md = strings.ReplaceAll(md, "\t", "    ")
+
+	p := &markdownParser{}
+	return &ADFNode{
+		Type:    "doc",
+		Version: 1,
+		Content: p.blocks(strings.Split(md, "\n")),
+	}
+}
+
+type markdownParser struct{}
+
+// blocks parses a run of lines into block nodes
+func (p *markdownParser) blocks(lines []string) []ADFNode {
+	nodes := []ADFNode{}
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			i++
+
+		case mdFence.MatchString(trimmed):
+			node, next := p.codeBlock(lines, i)
+			nodes = append(nodes, node)
+			i = next
+
+		case mdHeading.MatchString(trimmed):
+			m := mdHeading.FindStringSubmatch(trimmed)
+			nodes = append(nodes, ADFNode{
+				Type:    "heading",
+				Attrs:   map[string]any{"level": len(m[1])},
+				Content: parseInline(m[2]),
+			})
+			i++
+
+		case mdRule.MatchString(line):
+			nodes = append(nodes, ADFNode{Type: "rule"})
+			i++
+
+		case strings.HasPrefix(trimmed, ">"):
+			node, next := p.blockquote(lines, i)
+			nodes = append(nodes, node)
+			i = next
+
+		case strings.HasPrefix(trimmed, "|") && i+1 < len(lines) && mdTableSep.MatchString(strings.TrimSpace(lines[i+1])):
+			node, next := p.table(lines, i)
+			nodes = append(nodes, node)
+			i = next
+
+		case mdListItem.MatchString(line):
+			node, next := p.list(lines, i)
+			nodes = append(nodes, node)
+			i = next
+
+		default:
+			node, next := p.paragraph(lines, i)
+			nodes = append(nodes, node)
+			i = next
+		}
+	}
+	return nodes
+}
+
+// startsBlock reports whether line would interrupt a paragraph
+func startsBlock(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed == "" ||
+		mdFence.MatchString(trimmed) ||
+		mdHeading.MatchString(trimmed) ||
+		mdRule.MatchString(line) ||
+		strings.HasPrefix(trimmed, ">") ||
+		mdListItem.MatchString(line)
+}
+
+func (p *markdownParser) paragraph(lines []string, i int) (ADFNode, int) {
+	var text []string
+	for ; i < len(lines); i++ {
+		if len(text) > 0 && startsBlock(lines[i]) {
+			break
+		}
+		text = append(text, strings.TrimLeft(lines[i], " "))
+	}
+	return ADFNode{Type: "paragraph", Content: parseInlineLines(text)}, i
+}
+
+// parseInlineLines joins paragraph lines, turning trailing backslashes or double spaces into hard breaks
+func parseInlineLines(lines []string) []ADFNode {
+	var nodes []ADFNode
+	for i, line := range lines {
+		hardBreak := false
+		if i < len(lines)-1 {
+			if strings.HasSuffix(line, `\`) && !strings.HasSuffix(line, `\\`) {
+				line = strings.TrimSuffix(line, `\`)
+				hardBreak = true
+			} else if strings.HasSuffix(line, "  ") {
+				hardBreak = true
+			}
+		}
+		line = strings.TrimRight(line, " ")
+
+		nodes = append(nodes, parseInline(line)...)
+		if i < len(lines)-1 {
+			if hardBreak {
+				nodes = append(nodes, ADFNode{Type: "hardBreak"})
+			} else {
+				nodes = append(nodes, ADFNode{Type: "text", Text: " "})
+			}
+		}
+	}
+	return mergeText(nodes)
+}
+
+func (p *markdownParser) codeBlock(lines []string, i int) (ADFNode, int) {
+	m := mdFence.FindStringSubmatch(strings.TrimSpace(lines[i]))
+	fence := m[1]
+	indent := len(lines[i]) - len(strings.TrimLeft(lines[i], " "))
+
+	var code []string
+	for i++; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			i++
+			break
+		}
+		line := lines[i]
+		for n := 0; n < indent && strings.HasPrefix(line, " "); n++ {
+			line = line[1:]
+		}
+		code = append(code, line)
+	}
+
+	node := ADFNode{Type: "codeBlock"}
+	if m[2] != "" {
+		node.Attrs = map[string]any{"language": m[2]}
+	}
+	if text := strings.Join(code, "\n"); text != "" {
+		node.Content = []ADFNode{{Type: "text", Text: text}}
+	}
+	return node, i
+}
+
+// blockquote parses a quote; a quote that opens with "**Info:**" and friends becomes a panel
+func (p *markdownParser) blockquote(lines []string, i int) (ADFNode, int) {
+	var inner []string
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(trimmed, ">") {
+			break
+		}
+		trimmed = strings.TrimPrefix(trimmed, ">")
+		inner = append(inner, strings.TrimPrefix(trimmed, " "))
+	}
+
+	if len(inner) > 0 {
+		if m := mdPanelHeader.FindStringSubmatch(strings.TrimSpace(inner[0])); m != nil {
+			return ADFNode{
+				Type:    "panel",
+				Attrs:   map[string]any{"panelType": strings.ToLower(m[1])},
+				Content: p.blocks(inner[1:]),
+			}, i
+		}
+	}
+	return ADFNode{Type: "blockquote", Content: p.blocks(inner)}, i
+}
+
+func (p *markdownParser) table(lines []string, i int) (ADFNode, int) {
+	header := splitTableRow(lines[i])
+	table := ADFNode{
+		Type:  "table",
+		Attrs: map[string]any{"isNumberColumnEnabled": false, "layout": "default"},
+	}
+	table.Content = append(table.Content, tableRow(header, "tableHeader"))
+
+	for i += 2; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(trimmed, "|") {
+			break
+		}
+		cells := splitTableRow(trimmed)
+		for len(cells) < len(header) {
+			cells = append(cells, "")
+		}
+		table.Content = append(table.Content, tableRow(cells[:len(header)], "tableCell"))
+	}
+	return table, i
+}
+
+func tableRow(cells []string, cellType string) ADFNode {
+	row := ADFNode{Type: "tableRow"}
+	for _, cell := range cells {
+		para := ADFNode{Type: "paragraph", Content: parseInline(cell)}
+		row.Content = append(row.Content, ADFNode{Type: cellType, Content: []ADFNode{para}})
+	}
+	return row
+}
+
+// splitTableRow splits a pipe table row on unescaped pipes
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// list parses a bullet, ordered or task list starting at line i
+func (p *markdownParser) list(lines []string, i int) (ADFNode, int) {
+	first := mdListItem.FindStringSubmatch(lines[i])
+	baseIndent := len(first[1])
+	ordered := !strings.ContainsAny(first[2], "-*+")
+
+	list := ADFNode{Type: "bulletList"}
+	if ordered {
+		list.Type = "orderedList"
+		if start := atoiPrefix(first[2]); start != 1 {
+			list.Attrs = map[string]any{"order": start}
+		}
+	}
+
+	var items [][]string
+	for i < len(lines) {
+		m := mdListItem.FindStringSubmatch(lines[i])
+		if m == nil || len(m[1]) != baseIndent || ordered == strings.ContainsAny(m[2], "-*+") {
+			break
+		}
+
+		contentIndent := len(m[0])
+		if m[3] == "" {
+			contentIndent++
+		}
+		item := []string{lines[i][len(m[0]):]}
+		i++
+
+		// Continuation lines are either indented past the marker or lazy paragraph text
+		for i < len(lines) {
+			line := lines[i]
+			indent := len(line) - len(strings.TrimLeft(line, " "))
+			if strings.TrimSpace(line) == "" {
+				if i+1 < len(lines) && leadingSpaces(lines[i+1]) >= contentIndent && strings.TrimSpace(lines[i+1]) != "" {
+					item = append(item, "")
+					i++
+					continue
+				}
+				break
+			}
+			if indent >= contentIndent {
+				item = append(item, line[contentIndent:])
+				i++
+				continue
+			}
+			if indent > baseIndent || !startsBlock(line) && !strings.HasPrefix(strings.TrimSpace(line), "|") {
+				item = append(item, strings.TrimLeft(line, " "))
+				i++
+				continue
+			}
+			break
+		}
+		items = append(items, item)
+
+		// A single blank line between items keeps the list going
+		if i+1 < len(lines) && strings.TrimSpace(lines[i]) == "" {
+			if m := mdListItem.FindStringSubmatch(lines[i+1]); m != nil && len(m[1]) == baseIndent {
+				i++
+			}
+		}
+	}
+
+	if !ordered && isTaskList(items) {
+		return taskList(items), i
+	}
+
+	for _, item := range items {
+		list.Content = append(list.Content, ADFNode{Type: "listItem", Content: p.listItemBlocks(item)})
+	}
+	return list, i
+}
+
+// listItemBlocks parses the content of a list item; an empty item still needs a paragraph
+func (p *markdownParser) listItemBlocks(lines []string) []ADFNode {
+	blocks := p.blocks(lines)
+	if len(blocks) == 0 || blocks[0].Type != "paragraph" && blocks[0].Type != "codeBlock" {
+		blocks = append([]ADFNode{{Type: "paragraph"}}, blocks...)
+	}
+	return blocks
+}
+
+func isTaskList(items [][]string) bool {
+	for _, item := range items {
+		if !mdTaskBox.MatchString(item[0] + " ") {
+			return false
+		}
+	}
+	return len(items) > 0
+}
+
+func taskList(items [][]string) ADFNode {
+	list := ADFNode{Type: "taskList", Attrs: map[string]any{"localId": ""}}
+	for _, item := range items {
+		m := mdTaskBox.FindStringSubmatch(item[0] + " ")
+		state := "TODO"
+		if m[1] != " " {
+			state = "DONE"
+		}
+		text := append([]string{strings.TrimPrefix(item[0], m[0][:3])}, item[1:]...)
+		text[0] = strings.TrimPrefix(text[0], " ")
+		list.Content = append(list.Content, ADFNode{
+			Type:    "taskItem",
+			Attrs:   map[string]any{"localId": "", "state": state},
+			Content: parseInlineLines(trimLines(text)),
+		})
+	}
+	return list
+}
+
+func trimLines(lines []string) []string {
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if line = strings.TrimLeft(line, " "); line != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+func leadingSpaces(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+func atoiPrefix(s string) int {
+	n := 0
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			break
+		}
+		n = n*10 + int(r-'0')
+	}
+	return n
+}
+
+// --- Inline parsing ---
+
+// parseInline converts a single line of Markdown inline syntax into ADF inline nodes
+func parseInline(s string) []ADFNode {
+	return mergeText(parseInlineMarks(s, nil))
+}
+
+func parseInlineMarks(s string, marks []ADFMark) []ADFNode {
+	var nodes []ADFNode
+	var text strings.Builder
+
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, textNode(text.String(), marks))
+			text.Reset()
+		}
+	}
+
+	for i := 0; i < len(s); {
+		c := s[i]
+
+		switch {
+		case c == '\\' && i+1 < len(s) && isMarkdownPunct(s[i+1]):
+			text.WriteByte(s[i+1])
+			i += 2
+			continue
+
+		case c == '`':
+			if code, next, ok := parseCodeSpan(s, i); ok {
+				flush()
+				nodes = append(nodes, textNode(code, append(linkMarks(marks), ADFMark{Type: "code"})))
+				i = next
+				continue
+			}
+
+		case c == '<':
+			if end := strings.IndexByte(s[i:], '>'); end > 0 {
+				url := s[i+1 : i+end]
+				if strings.Contains(url, "://") && !strings.ContainsAny(url, " <") {
+					flush()
+					nodes = append(nodes, ADFNode{Type: "inlineCard", Attrs: map[string]any{"url": url}})
+					i += end + 1
+					continue
+				}
+			}
+
+		case c == '[' || c == '!' && i+1 < len(s) && s[i+1] == '[':
+			start := i
+			if c == '!' {
+				start++
+			}
+			if label, href, next, ok := parseLink(s, start); ok {
+				flush()
+				if strings.HasPrefix(href, "accountid:") && strings.HasPrefix(label, "@") {
+					nodes = append(nodes, ADFNode{
+						Type: "mention",
+						Attrs: map[string]any{
+							"id":   strings.TrimPrefix(href, "accountid:"),
+							"text": "@" + unescapeMarkdown(strings.TrimPrefix(label, "@")),
+						},
+					})
+				} else {
+					linkMark := ADFMark{Type: "link", Attrs: map[string]any{"href": href}}
+					nodes = append(nodes, parseInlineMarks(label, append(marks, linkMark))...)
+				}
+				i = next
+				continue
+			}
+
+		case c == '*' || c == '_' || c == '~':
+			if inner, markType, next, ok := parseEmphasis(s, i); ok {
+				flush()
+				nodes = append(nodes, parseInlineMarks(inner, append(marks, ADFMark{Type: markType}))...)
+				i = next
+				continue
+			}
+		}
+
+		text.WriteByte(c)
+		i++
+	}
+	flush()
+	return nodes
+}
+
+func textNode(text string, marks []ADFMark) ADFNode {
+	node := ADFNode{Type: "text", Text: text}
+	if len(marks) > 0 {
+		node.Marks = append([]ADFMark(nil), marks...)
+	}
+	return node
+}
+
+// linkMarks keeps only link marks, since ADF only allows code to combine with links
+func linkMarks(marks []ADFMark) []ADFMark {
+	var out []ADFMark
+	for _, m := range marks {
+		if m.Type == "link" {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+func parseCodeSpan(s string, i int) (string, int, bool) {
+	n := 0
+	for i+n < len(s) && s[i+n] == '`' {
+		n++
+	}
+	fence := s[i : i+n]
+
+	for j := i + n; j < len(s); {
+		k := strings.Index(s[j:], fence)
+		if k < 0 {
+			return "", 0, false
+		}
+		end := j + k
+		// The closing run must be exactly as long as the opening one
+		if end+n < len(s) && s[end+n] == '`' {
+			j = end + n
+			for j < len(s) && s[j] == '`' {
+				j++
+			}
+			continue
+		}
+		code := s[i+n : end]
+		if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' {
+			code = code[1 : len(code)-1]
+		}
+		return code, end + n, true
+	}
+	return "", 0, false
+}
+
+// parseLink parses [label](href) starting at the opening bracket
+func parseLink(s string, i int) (string, string, int, bool) {
+	depth := 0
+	for j := i; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				if j+1 >= len(s) || s[j+1] != '(' {
+					return "", "", 0, false
+				}
+				end := strings.IndexByte(s[j+2:], ')')
+				if end < 0 {
+					return "", "", 0, false
+				}
+				href := strings.TrimSpace(s[j+2 : j+2+end])
+				return s[i+1 : j], href, j + 3 + end, true
+			}
+		}
+	}
+	return "", "", 0, false
+}
+
+// parseEmphasis parses **strong**, *em*, _em_, __strong__ and ~~strike~~ starting at i
+func parseEmphasis(s string, i int) (string, string, int, bool) {
+	c := s[i]
+	n := 1
+	if i+1 < len(s) && s[i+1] == c {
+		n = 2
+	}
+	if c == '~' && n != 2 {
+		return "", "", 0, false
+	}
+	delim := s[i : i+n]
+
+	open := i + n
+	if open >= len(s) || s[open] == ' ' {
+		return "", "", 0, false
+	}
+	// Intraword underscores are literal, as in snake_case identifiers
+	if c == '_' && i > 0 && isWordChar(s[i-1]) {
+		return "", "", 0, false
+	}
+
+	for j := open + 1; j <= len(s)-n; j++ {
+		if s[j] == '\\' {
+			j++
+			continue
+		}
+		if s[j] == '`' {
+			if _, next, ok := parseCodeSpan(s, j); ok {
+				j = next - 1
+				continue
+			}
+		}
+		if s[j:j+n] != delim || s[j-1] == ' ' {
+			continue
+		}
+		// Don't close a single * on the first half of a ** pair
+		if n == 1 && j+1 < len(s) && s[j+1] == c {
+			j++
+			continue
+		}
+		if c == '_' && j+n < len(s) && isWordChar(s[j+n]) {
+			continue
+		}
+
+		markType := "em"
+		switch {
+		case c == '~':
+			markType = "strike"
+		case n == 2:
+			markType = "strong"
+		}
+		return s[open:j], markType, j + n, true
+	}
+	return "", "", 0, false
+}
+
+func isWordChar(b byte) bool {
+	return b >= utf8.RuneSelf || unicode.IsLetter(rune(b)) || unicode.IsDigit(rune(b))
+}
+
+func isMarkdownPunct(b byte) bool {
+	return strings.IndexByte("\\`*_{}[]()#+-.!|~<>", b) >= 0
+}
+
+func unescapeMarkdown(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && isMarkdownPunct(s[i+1]) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// mergeText joins adjacent text nodes that carry the same marks
+func mergeText(nodes []ADFNode) []ADFNode {
+	var out []ADFNode
+	for _, n := range nodes {
+		if n.Type == "text" && n.Text == "" {
+			continue
+		}
+		if len(out) > 0 {
+			last := &out[len(out)-1]
+			if n.Type == "text" && last.Type == "text" && sameMarks(last.Marks, n.Marks) {
+				last.Text += n.Text
+				continue
+			}
+		}
+		out = append(out, n)
+	}
+	return out
+}
+
+func sameMarks(a, b []ADFMark) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Type != b[i].Type || attrString(a[i].Attrs, "href") != attrString(b[i].Attrs, "href") {
+			return false
+		}
+	}
+	return true
+}
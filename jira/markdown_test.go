@@ -0,0 +1,69 @@
+package jira
+
+import "testing"
+
+func TestMarkdownRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		// want is the rendered Markdown, when it differs from in
+		want string
+	}{
+		{name: "headings", in: "# Title\n\n## Sub\n\n### Third"},
+		{name: "bullet list", in: "- one\n- two\n- three"},
+		{name: "nested bullet list", in: "- one\n- two\n  - nested\n  - nested two\n- three"},
+		{name: "nested ordered list", in: "1. first\n2. second\n   1. inner"},
+		{name: "ordered list", in: "1. first\n2. second\n3. third"},
+		{name: "ordered list in bullet list", in: "- one\n  1. inner first\n  2. inner second\n- two"},
+		{name: "bullet list in ordered list", in: "1. first\n   - bullet\n     - deeper\n2. second"},
+		{name: "deeply nested list", in: "- a\n  - b\n    - c\n      - d"},
+		{name: "mention", in: "Thanks [@Anna Smith](accountid:5b10ac8d82e05b22cc7d4ef5), please review"},
+		{name: "mention in list", in: "- [@Anna](accountid:abc) owns this\n- nobody else"},
+		{name: "panel", in: "> **Info:**\n>\n> Deploys are frozen"},
+		{name: "panel with list", in: "> **Warning:**\n>\n> - first\n> - second"},
+		{name: "task list", in: "- [ ] todo\n- [x] done"},
+		{name: "code block with language", in: "```go\nfunc main() {}\n```"},
+		{name: "code block without language", in: "```\nplain\n```"},
+		{name: "link", in: "See [the docs](https://example.com/docs) now"},
+		{name: "emphasis in list", in: "- **bold** item\n- *italic* and `code`"},
+		{name: "strikethrough", in: "~~gone~~ and kept"},
+		{name: "blockquote", in: "> quoted text"},
+		{name: "table", in: "| A | B |\n| --- | --- |\n| 1 | 2 |"},
+		{name: "hard break", in: "line one\\\nline two"},
+		{name: "hard break with trailing spaces", in: "line one  \nline two", want: "line one\\\nline two"},
+		{name: "paragraphs", in: "first paragraph\n\nsecond paragraph"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.want
+			if want == "" {
+				want = tt.in
+			}
+			if got := RenderADFMarkdown(MarkdownToADF(tt.in)); got != want {
+				t.Errorf("round trip of %q:\ngot:\n%s\nwant:\n%s", tt.in, got, want)
+			}
+		})
+	}
+}
+
+func TestMarkdownToADFCodeBlockLanguage(t *testing.T) {
+	doc := MarkdownToADF("```go\nfunc main() {}\n```")
+	if len(doc.Content) != 1 || doc.Content[0].Type != "codeBlock" {
+		t.Fatalf("expected a single codeBlock, got %+v", doc.Content)
+	}
+	if lang := doc.Content[0].Attrs["language"]; lang != "go" {
+		t.Errorf("language = %v, want go", lang)
+	}
+}
+
+func TestMarkdownToADFMention(t *testing.T) {
+	doc := MarkdownToADF("Thanks [@Anna Smith](accountid:abc123)")
+	if len(doc.Content) != 1 || len(doc.Content[0].Content) != 2 {
+		t.Fatalf("expected a paragraph with text and a mention, got %+v", doc.Content)
+	}
+	mention := doc.Content[0].Content[1]
+	if mention.Type != "mention" || mention.Attrs["id"] != "abc123" || mention.Attrs["text"] != "@Anna Smith" {
+		t.Errorf("mention = %+v, want id abc123 and text @Anna Smith", mention)
+	}
+}
@@ -0,0 +1,219 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	oauthAuthorizeURL = "https://auth.atlassian.com/authorize"
+	oauthTokenURL     = "https://auth.atlassian.com/oauth/token"
+	oauthResourcesURL = "https://api.atlassian.com/oauth/token/accessible-resources"
+	oauthAPIURL       = "https://api.atlassian.com/ex/jira/"
+)
+
+// OAuthScopes are the scopes jig asks for: issues, users and the Jira Software agile API,
+// plus offline_access for a refresh token
+var OAuthScopes = []string{
+	"read:jira-work",
+	"write:jira-work",
+	"read:jira-user",
+	"read:board-scope:jira-software",
+	"read:board-scope.admin:jira-software",
+	"read:sprint:jira-software",
+	"write:sprint:jira-software",
+	"read:issue-details:jira",
+	"write:board-scope:jira-software",
+	"offline_access",
+}
+
+var oauthHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// OAuthConfig identifies an OAuth 2.0 (3LO) app registered in the Atlassian developer console
+type OAuthConfig struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+type OAuthToken struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	Expiry       time.Time `json:"expiry"`
+}
+
+// CloudResource is a site an OAuth token grants access to
+type CloudResource struct {
+	ID     string   `json:"id"`
+	URL    string   `json:"url"`
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+// PKCEChallenge returns the S256 code challenge for a code verifier
+func PKCEChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthCodeURL returns the consent page to send the user to
+func (o OAuthConfig) AuthCodeURL(state, verifier string) string {
+	params := url.Values{}
+	params.Set("audience", "api.atlassian.com")
+	params.Set("client_id", o.ClientID)
+	params.Set("scope", strings.Join(o.Scopes, " "))
+	params.Set("redirect_uri", o.RedirectURL)
+	params.Set("state", state)
+	params.Set("response_type", "code")
+	params.Set("prompt", "consent")
+	params.Set("code_challenge", PKCEChallenge(verifier))
+	params.Set("code_challenge_method", "S256")
+	return oauthAuthorizeURL + "?" + params.Encode()
+}
+
+// Exchange trades an authorization code for tokens
+func (o OAuthConfig) Exchange(ctx context.Context, code, verifier string) (*OAuthToken, error) {
+	return o.requestToken(ctx, map[string]string{
+		"grant_type":    "authorization_code",
+		"code":          code,
+		"redirect_uri":  o.RedirectURL,
+		"code_verifier": verifier,
+	})
+}
+
+// Refresh trades a refresh token for new tokens; Atlassian rotates the refresh token too
+func (o OAuthConfig) Refresh(ctx context.Context, refreshToken string) (*OAuthToken, error) {
+	return o.requestToken(ctx, map[string]string{
+		"grant_type":    "refresh_token",
+		"refresh_token": refreshToken,
+	})
+}
+
+func (o OAuthConfig) requestToken(ctx context.Context, payload map[string]string) (*OAuthToken, error) {
+	payload["client_id"] = o.ClientID
+	if o.ClientSecret != "" {
+		payload["client_secret"] = o.ClientSecret
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", oauthTokenURL, bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := oauthHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token request returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var tokenResp struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return nil, err
+	}
+
+	return &OAuthToken{
+		AccessToken:  tokenResp.AccessToken,
+		RefreshToken: tokenResp.RefreshToken,
+		Expiry:       time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second),
+	}, nil
+}
+
+// GetAccessibleResources returns the sites an access token can be used with
+func GetAccessibleResources(ctx context.Context, accessToken string) ([]CloudResource, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", oauthResourcesURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	BearerAuth{Token: accessToken}.Authenticate(req)
+
+	resp, err := oauthHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var resources []CloudResource
+	if err := json.Unmarshal(body, &resources); err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+// CloudAPIURLs returns the base and agile URLs for a site accessed with OAuth, which go
+// through api.atlassian.com rather than the site itself
+func CloudAPIURLs(cloudID string) (string, string) {
+	site := oauthAPIURL + cloudID
+	return site + "/rest/api/3", site + "/rest/agile/1.0"
+}
+
+// OAuthAuth authenticates with an OAuth access token, refreshing it shortly before it expires
+type OAuthAuth struct {
+	Config OAuthConfig
+	Token  OAuthToken
+	// OnRefresh is called with new tokens so they can be stored; the old refresh token no
+	// longer works once it has been used
+	OnRefresh func(OAuthToken) error
+
+	mu sync.Mutex
+}
+
+func (a *OAuthAuth) Authenticate(req *http.Request) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if time.Until(a.Token.Expiry) < time.Minute && a.Token.RefreshToken != "" {
+		token, err := a.Config.Refresh(req.Context(), a.Token.RefreshToken)
+		if err != nil {
+			return fmt.Errorf("failed to refresh OAuth token: %v; run jig auth login --oauth", err)
+		}
+		if token.RefreshToken == "" {
+			token.RefreshToken = a.Token.RefreshToken
+		}
+		a.Token = *token
+		if a.OnRefresh != nil {
+			if err := a.OnRefresh(a.Token); err != nil {
+				return err
+			}
+		}
+	}
+
+	return BearerAuth{Token: a.Token.AccessToken}.Authenticate(req)
+}
@@ -0,0 +1,52 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// Project permissions jig needs for its everyday commands
+const (
+	PermissionBrowseProjects   = "BROWSE_PROJECTS"
+	PermissionTransitionIssues = "TRANSITION_ISSUES"
+	PermissionAssignIssues     = "ASSIGN_ISSUES"
+)
+
+type MyPermissionsResponse struct {
+	Permissions map[string]struct {
+		Key            string `json:"key"`
+		Name           string `json:"name"`
+		HavePermission bool   `json:"havePermission"`
+	} `json:"permissions"`
+}
+
+// GetMyPermissions reports which of the given permissions the authenticated user has in a
+// project, bypassing any cache
+func (c *Client) GetMyPermissions(ctx context.Context, projectKey string, permissions ...string) (map[string]bool, error) {
+	params := url.Values{}
+	params.Set("projectKey", projectKey)
+	params.Set("permissions", strings.Join(permissions, ","))
+
+	u, err := c.baseURL.Parse("mypermissions?" + params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.makeRequest(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var permResp MyPermissionsResponse
+	if err := json.Unmarshal(body, &permResp); err != nil {
+		return nil, err
+	}
+
+	granted := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		granted[permission] = permResp.Permissions[permission].HavePermission
+	}
+	return granted, nil
+}
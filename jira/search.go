@@ -0,0 +1,91 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+type SearchResponse struct {
+	Issues        []Issue `json:"issues"`
+	NextPageToken string  `json:"nextPageToken"`
+	IsLast        bool    `json:"isLast"`
+}
+
+// SearchIssues runs a JQL search and returns all matching issues with the requested fields
+func (c *Client) SearchIssues(ctx context.Context, jql string, fields ...string) ([]Issue, error) {
+	if c.isServer(ctx) {
+		return c.searchIssuesServer(ctx, jql, fields)
+	}
+
+	var issues []Issue
+	pageToken := ""
+	for {
+		query := url.Values{}
+		query.Set("jql", jql)
+		query.Set("maxResults", "100")
+		if len(fields) > 0 {
+			query.Set("fields", strings.Join(fields, ","))
+		}
+		if pageToken != "" {
+			query.Set("nextPageToken", pageToken)
+		}
+
+		u, err := c.baseURL.Parse("search/jql?" + query.Encode())
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := c.makeRequest(ctx, "GET", u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page SearchResponse
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, err
+		}
+
+		issues = append(issues, page.Issues...)
+		if page.IsLast || page.NextPageToken == "" {
+			return issues, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+// searchIssuesServer is SearchIssues for Server, which pages search by offset rather than token
+func (c *Client) searchIssuesServer(ctx context.Context, jql string, fields []string) ([]Issue, error) {
+	var issues []Issue
+	for {
+		query := url.Values{}
+		query.Set("jql", jql)
+		query.Set("maxResults", "100")
+		query.Set("startAt", fmt.Sprint(len(issues)))
+		if len(fields) > 0 {
+			query.Set("fields", strings.Join(fields, ","))
+		}
+
+		u, err := c.baseURL.Parse("search?" + query.Encode())
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := c.makeRequest(ctx, "GET", u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page IssuesResponse
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, err
+		}
+
+		issues = append(issues, page.Issues...)
+		if len(page.Issues) == 0 || len(issues) >= page.Total {
+			return issues, nil
+		}
+	}
+}
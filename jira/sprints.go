@@ -0,0 +1,107 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// sprintTimeFormat is the timestamp layout of the agile sprint API
+const sprintTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// GetBoardSprints returns every sprint of a board in the given states (all states when none are given),
+// in board order
+func (c *Client) GetBoardSprints(ctx context.Context, boardID int, states ...string) ([]Sprint, error) {
+	var sprints []Sprint
+	for startAt := 0; ; {
+		path := fmt.Sprintf("board/%d/sprint?startAt=%d", boardID, startAt)
+		if len(states) > 0 {
+			path += "&state=" + strings.Join(states, ",")
+		}
+
+		u, err := c.agileURL.Parse(path)
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := c.makeRequest(ctx, "GET", u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page SprintsResponse
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, err
+		}
+
+		sprints = append(sprints, page.Values...)
+		startAt += len(page.Values)
+		if page.IsLast || len(page.Values) == 0 {
+			return sprints, nil
+		}
+	}
+}
+
+// MoveIssuesToBacklog removes issues from whatever sprint they are in
+func (c *Client) MoveIssuesToBacklog(ctx context.Context, issueKeys []string) error {
+	payload := map[string]any{
+		"issues": issueKeys,
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	u, err := c.agileURL.Parse("backlog/issue")
+	if err != nil {
+		return err
+	}
+
+	_, err = c.makeRequest(ctx, "POST", u.String(), bytes.NewReader(jsonData))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Client) updateSprint(ctx context.Context, sprintID int, payload map[string]any) error {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	u, err := c.agileURL.Parse(fmt.Sprintf("sprint/%d", sprintID))
+	if err != nil {
+		return err
+	}
+
+	_, err = c.makeRequest(ctx, "POST", u.String(), bytes.NewReader(jsonData))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// StartSprint activates a future sprint; goal is left unchanged when empty
+func (c *Client) StartSprint(ctx context.Context, sprintID int, start, end time.Time, goal string) error {
+	payload := map[string]any{
+		"state":     "active",
+		"startDate": start.Format(sprintTimeFormat),
+		"endDate":   end.Format(sprintTimeFormat),
+	}
+	if goal != "" {
+		payload["goal"] = goal
+	}
+	return c.updateSprint(ctx, sprintID, payload)
+}
+
+// CloseSprint completes an active sprint
+func (c *Client) CloseSprint(ctx context.Context, sprintID int) error {
+	return c.updateSprint(ctx, sprintID, map[string]any{"state": "closed"})
+}
@@ -1,26 +1,51 @@
 package jira
 
 type Sprint struct {
-	ID    int    `json:"id"`
-	Name  string `json:"name"`
-	State string `json:"state"`
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	State        string `json:"state"`
+	Goal         string `json:"goal"`
+	StartDate    string `json:"startDate"`
+	EndDate      string `json:"endDate"`
+	CompleteDate string `json:"completeDate"`
 }
 
 type SprintsResponse struct {
-	Values []Sprint `json:"values"`
+	StartAt int      `json:"startAt"`
+	IsLast  bool     `json:"isLast"`
+	Values  []Sprint `json:"values"`
+}
+
+// Status is an issue's workflow status; its category (new, indeterminate or done) is the same
+// across workflows whatever the status is called
+type Status struct {
+	ID             string         `json:"id"`
+	Name           string         `json:"name"`
+	StatusCategory StatusCategory `json:"statusCategory"`
+}
+
+type StatusCategory struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
 }
 
 type Issue struct {
 	Key    string `json:"key"`
 	Fields struct {
-		Summary string `json:"summary"`
-		Status  struct {
-			Name string `json:"name"`
-		} `json:"status"`
+		Summary  string `json:"summary"`
+		Status   Status `json:"status"`
 		Assignee struct {
 			DisplayName string `json:"displayName"`
 		} `json:"assignee"`
+		IssueType struct {
+			Name    string `json:"name"`
+			Subtask bool   `json:"subtask"`
+		} `json:"issuetype"`
+		Labels     []string    `json:"labels"`
+		IssueLinks []IssueLink `json:"issuelinks"`
 	} `json:"fields"`
+	// CustomFields holds the non-null customfield_* values, keyed by field ID
+	CustomFields map[string]any `json:"-"`
 }
 
 type DetailedIssue struct {
@@ -28,9 +53,7 @@ type DetailedIssue struct {
 	Fields struct {
 		Summary     string `json:"summary"`
 		Description any    `json:"description"`
-		Status      struct {
-			Name string `json:"name"`
-		} `json:"status"`
+		Status      Status `json:"status"`
 		Assignee struct {
 			DisplayName  string `json:"displayName"`
 			EmailAddress string `json:"emailAddress"`
@@ -41,20 +64,77 @@ type DetailedIssue struct {
 		Priority struct {
 			Name string `json:"name"`
 		} `json:"priority"`
-		Labels  []string `json:"labels"`
-		Created string   `json:"created"`
-		Updated string   `json:"updated"`
+		Labels      []string `json:"labels"`
+		FixVersions []struct {
+			Name string `json:"name"`
+		} `json:"fixVersions"`
+		Attachment []Attachment `json:"attachment"`
+		IssueLinks []IssueLink  `json:"issuelinks"`
+		Created    string       `json:"created"`
+		Updated    string       `json:"updated"`
 	} `json:"fields"`
+	CustomFields map[string]any `json:"-"`
 }
 
 type IssuesResponse struct {
-	Issues []Issue `json:"issues"`
+	StartAt int     `json:"startAt"`
+	Total   int     `json:"total"`
+	Issues  []Issue `json:"issues"`
 }
 
 type IssueType struct {
-	ID      string `json:"id"`
-	Name    string `json:"name"`
-	Subtask bool   `json:"subtask"`
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Description    string `json:"description"`
+	Subtask        bool   `json:"subtask"`
+	HierarchyLevel int    `json:"hierarchyLevel"`
+}
+
+// IssueTypesResponse is paginated as issueTypes on Cloud and values on Server
+type IssueTypesResponse struct {
+	IssueTypes []IssueType `json:"issueTypes"`
+	Values     []IssueType `json:"values"`
+}
+
+// FieldMeta describes a field as returned by createmeta and editmeta
+type FieldMeta struct {
+	FieldID         string         `json:"fieldId"`
+	Key             string         `json:"key"`
+	Name            string         `json:"name"`
+	Required        bool           `json:"required"`
+	HasDefaultValue bool           `json:"hasDefaultValue"`
+	Schema          FieldSchema    `json:"schema"`
+	AllowedValues   []AllowedValue `json:"allowedValues"`
+	Operations      []string       `json:"operations"`
+}
+
+type FieldSchema struct {
+	Type     string `json:"type"`
+	Items    string `json:"items"`
+	System   string `json:"system"`
+	Custom   string `json:"custom"`
+	CustomID int    `json:"customId"`
+}
+
+// AllowedValue is one option of a select-like field; depending on the field it carries a name or a value
+type AllowedValue struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Label returns the human readable text of an allowed value
+func (v AllowedValue) Label() string {
+	if v.Name != "" {
+		return v.Name
+	}
+	return v.Value
+}
+
+// CreateMetaFieldsResponse is paginated as fields on Cloud and values on Server
+type CreateMetaFieldsResponse struct {
+	Fields []FieldMeta `json:"fields"`
+	Values []FieldMeta `json:"values"`
 }
 
 type JiraProject struct {
@@ -76,9 +156,7 @@ type BoardsResponse struct {
 type Transition struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
-	To   struct {
-		Name string `json:"name"`
-	} `json:"to"`
+	To   Status `json:"to"`
 }
 
 type TransitionsResponse struct {
@@ -86,3 +164,14 @@ type TransitionsResponse struct {
 }
 
 
+
+type EditMetaResponse struct {
+	Fields map[string]FieldMeta `json:"fields"`
+}
+
+// IssueUpdate is the body of an issue edit: Fields replaces values, Update applies
+// operations such as {"labels": [{"add": "x"}]}
+type IssueUpdate struct {
+	Fields map[string]any              `json:"fields,omitempty"`
+	Update map[string][]map[string]any `json:"update,omitempty"`
+}
@@ -0,0 +1,147 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+type User struct {
+	AccountID string `json:"accountId"`
+	// Name is the username on Server and Data Center, which have no account IDs
+	Name         string `json:"name,omitempty"`
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress"`
+	Active       bool   `json:"active"`
+}
+
+// ID identifies the user in API calls: the account ID on Cloud, the username on Server
+func (u User) ID() string {
+	if u.AccountID != "" {
+		return u.AccountID
+	}
+	return u.Name
+}
+
+// UserValue is a user given for a field, identified like User.ID: by account ID on Cloud
+// and by username on Server. The client sends it in the shape the instance expects
+type UserValue string
+
+// userField returns the JSON for a user in a payload: an accountId on Cloud, a name on Server
+func (c *Client) userField(ctx context.Context, id string) map[string]any {
+	if c.isServer(ctx) {
+		return map[string]any{"name": id}
+	}
+	return map[string]any{"accountId": id}
+}
+
+// GetAssignableUsers returns the active users issues in a project can be assigned to,
+// narrowed by query (name or email prefix) unless it is empty
+func (c *Client) GetAssignableUsers(ctx context.Context, projectKey, query string) ([]User, error) {
+	const pageSize = 100
+
+	var users []User
+	for startAt := 0; ; startAt += pageSize {
+		params := url.Values{}
+		params.Set("project", projectKey)
+		params.Set("startAt", fmt.Sprint(startAt))
+		params.Set("maxResults", fmt.Sprint(pageSize))
+		switch {
+		case c.isServer(ctx):
+			// Server has no query parameter and requires username, which matches names and emails
+			params.Set("username", query)
+		case query != "":
+			params.Set("query", query)
+		}
+
+		u, err := c.baseURL.Parse("user/assignable/search?" + params.Encode())
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := c.makeRequest(ctx, "GET", u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []User
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, err
+		}
+
+		for _, user := range page {
+			if user.Active {
+				user.AccountID = user.ID()
+				users = append(users, user)
+			}
+		}
+		// Cloud filters pages after fetching them, so a short page doesn't mean the last one
+		if len(page) == 0 {
+			return users, nil
+		}
+	}
+}
+
+// AssignIssue assigns an issue to accountID (a username on Server), or unassigns it when
+// accountID is empty
+func (c *Client) AssignIssue(ctx context.Context, issueKey, accountID string) error {
+	idField := "accountId"
+	if c.isServer(ctx) {
+		idField = "name"
+	}
+	payload := map[string]any{
+		idField: nil,
+	}
+	if accountID != "" {
+		payload[idField] = accountID
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	u, err := c.baseURL.Parse(fmt.Sprintf("issue/%s/assignee", issueKey))
+	if err != nil {
+		return err
+	}
+
+	_, err = c.makeRequest(ctx, "PUT", u.String(), bytes.NewReader(jsonData))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// VerifyCredentials checks the configured credentials against Jira, bypassing any cache
+func (c *Client) VerifyCredentials(ctx context.Context) error {
+	_, err := c.Myself(ctx)
+	return err
+}
+
+// Myself returns the authenticated user, bypassing any cache
+func (c *Client) Myself(ctx context.Context) (*User, error) {
+	u, err := c.baseURL.Parse("myself")
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.makeRequest(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var user User
+	if err := json.Unmarshal(body, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// CurrentAccountID returns the account ID of the authenticated user
+func (c *Client) CurrentAccountID(ctx context.Context) (string, error) {
+	return c.getCurrentUser(ctx)
+}
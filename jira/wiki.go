@@ -0,0 +1,257 @@
+package jira
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ADFToWiki renders an ADF document as Jira wiki markup, the rich text format of
+// Jira Server and Data Center
+func ADFToWiki(doc *ADFNode) string {
+	if doc == nil {
+		return ""
+	}
+	r := &wikiRenderer{}
+	return strings.TrimRight(strings.Join(r.blocks(doc.Content), "\n"), "\n")
+}
+
+type wikiRenderer struct{}
+
+var wikiEscaper = strings.NewReplacer(
+	"{", `\{`,
+	"}", `\}`,
+	"[", `\[`,
+	"]", `\]`,
+	"*", `\*`,
+	"_", `\_`,
+	"|", `\|`,
+)
+
+func (r *wikiRenderer) blocks(nodes []ADFNode) []string {
+	var lines []string
+	for i, n := range nodes {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, r.block(n)...)
+	}
+	return lines
+}
+
+func (r *wikiRenderer) block(n ADFNode) []string {
+	switch n.Type {
+	case "paragraph":
+		return strings.Split(r.inline(n.Content), "\n")
+
+	case "heading":
+		level := attrInt(n.Attrs, "level", 1)
+		return []string{fmt.Sprintf("h%d. %s", level, strings.ReplaceAll(r.inline(n.Content), "\n", " "))}
+
+	case "bulletList", "orderedList", "taskList", "decisionList":
+		return r.list(n, "")
+
+	case "codeBlock":
+		lines := []string{"{code}"}
+		if language := attrString(n.Attrs, "language"); language != "" {
+			lines[0] = "{code:" + language + "}"
+		}
+		lines = append(lines, strings.Split(strings.TrimRight(plainText(n), "\n"), "\n")...)
+		return append(lines, "{code}")
+
+	case "blockquote":
+		lines := []string{"{quote}"}
+		lines = append(lines, r.blocks(n.Content)...)
+		return append(lines, "{quote}")
+
+	case "rule":
+		return []string{"----"}
+
+	case "panel":
+		macro := "info"
+		switch attrString(n.Attrs, "panelType") {
+		case "note", "warning", "error":
+			macro = "warning"
+		case "success":
+			macro = "tip"
+		}
+		lines := []string{"{" + macro + "}"}
+		lines = append(lines, r.blocks(n.Content)...)
+		return append(lines, "{"+macro+"}")
+
+	case "expand", "nestedExpand":
+		var lines []string
+		if title := attrString(n.Attrs, "title"); title != "" {
+			lines = append(lines, "*"+wikiEscaper.Replace(title)+"*")
+		}
+		return append(lines, r.blocks(n.Content)...)
+
+	case "table":
+		return r.table(n)
+
+	case "mediaSingle", "mediaGroup":
+		var lines []string
+		for _, c := range n.Content {
+			lines = append(lines, r.block(c)...)
+		}
+		return lines
+
+	case "media":
+		if name := attrString(n.Attrs, "alt"); name != "" {
+			return []string{"[^" + name + "]"}
+		}
+		return nil
+
+	case "blockCard", "embedCard":
+		return []string{"[" + attrString(n.Attrs, "url") + "]"}
+	}
+
+	if len(n.Content) > 0 && isInline(n.Content[0]) {
+		return strings.Split(r.inline(n.Content), "\n")
+	}
+	if n.Text != "" {
+		return strings.Split(r.inline([]ADFNode{n}), "\n")
+	}
+	return r.blocks(n.Content)
+}
+
+// list renders a list; wiki markup nests lists by repeating the markers, e.g. "*#" for a
+// numbered list inside a bullet list
+func (r *wikiRenderer) list(n ADFNode, prefix string) []string {
+	marker := "*"
+	if n.Type == "orderedList" {
+		marker = "#"
+	}
+	prefix += marker
+
+	var lines []string
+	for _, item := range n.Content {
+		if item.Type == "taskList" {
+			lines = append(lines, r.list(item, prefix)...)
+			continue
+		}
+
+		box := ""
+		if item.Type == "taskItem" {
+			box = `\[ \] `
+			if attrString(item.Attrs, "state") == "DONE" {
+				box = "(/) "
+			}
+		}
+
+		var text []string
+		var nested []string
+		for _, c := range item.Content {
+			switch c.Type {
+			case "bulletList", "orderedList", "taskList":
+				nested = append(nested, r.list(c, prefix)...)
+			default:
+				if isInline(c) {
+					text = append(text, r.inline([]ADFNode{c}))
+				} else {
+					text = append(text, strings.Join(r.block(c), " \\\\ "))
+				}
+			}
+		}
+		lines = append(lines, prefix+" "+box+strings.ReplaceAll(strings.Join(text, " "), "\n", " \\\\ "))
+		lines = append(lines, nested...)
+	}
+	return lines
+}
+
+// table renders a wiki table, e.g. "||Name||Value||" for a header row and "|a|b|" for the others
+func (r *wikiRenderer) table(n ADFNode) []string {
+	var lines []string
+	for _, row := range n.Content {
+		var b strings.Builder
+		sep := "|"
+		for _, c := range row.Content {
+			sep = "|"
+			if c.Type == "tableHeader" {
+				sep = "||"
+			}
+			var cell []string
+			for _, block := range c.Content {
+				cell = append(cell, r.block(block)...)
+			}
+			b.WriteString(sep + strings.Join(cell, " \\\\ "))
+		}
+		lines = append(lines, b.String()+sep)
+	}
+	return lines
+}
+
+func (r *wikiRenderer) inline(nodes []ADFNode) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		switch n.Type {
+		case "text":
+			b.WriteString(wikiText(n))
+		case "hardBreak":
+			b.WriteString("\n")
+		case "mention":
+			fmt.Fprintf(&b, "[~%s]", attrString(n.Attrs, "id"))
+		case "emoji", "date":
+			b.WriteString(plainText(n))
+		case "status":
+			b.WriteString("*[" + strings.ToUpper(attrString(n.Attrs, "text")) + "]*")
+		case "inlineCard":
+			b.WriteString("[" + attrString(n.Attrs, "url") + "]")
+		default:
+			if len(n.Content) > 0 {
+				b.WriteString(r.inline(n.Content))
+			} else {
+				b.WriteString(wikiEscaper.Replace(n.Text))
+			}
+		}
+	}
+	return b.String()
+}
+
+// wikiText wraps a text node in the wiki markup for its marks
+func wikiText(n ADFNode) string {
+	var code, strong, em, strike bool
+	href := ""
+	for _, m := range n.Marks {
+		switch m.Type {
+		case "code":
+			code = true
+		case "strong":
+			strong = true
+		case "em":
+			em = true
+		case "strike":
+			strike = true
+		case "link":
+			href = attrString(m.Attrs, "href")
+		}
+	}
+
+	if code {
+		// Monospaced text is still parsed for markup, so escape it as well
+		return "{{" + wikiEscaper.Replace(n.Text) + "}}"
+	}
+
+	text := wikiEscaper.Replace(n.Text)
+	// Effects must hug the text, so keep surrounding spaces outside them
+	core := strings.TrimSpace(text)
+	if core == "" {
+		return text
+	}
+	lead := text[:strings.Index(text, core)]
+	trail := text[len(lead)+len(core):]
+	text = core
+
+	if em {
+		text = "_" + text + "_"
+	}
+	if strong {
+		text = "*" + text + "*"
+	}
+	if strike {
+		text = "-" + text + "-"
+	}
+	if href != "" {
+		text = "[" + text + "|" + href + "]"
+	}
+	return lead + text + trail
+}
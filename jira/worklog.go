@@ -0,0 +1,88 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// worklogTimeFormat is the timestamp layout Jira expects for worklog start times
+const worklogTimeFormat = "2006-01-02T15:04:05.000-0700"
+
+type Worklog struct {
+	ID               string `json:"id"`
+	Author           User   `json:"author"`
+	Comment          any    `json:"comment"`
+	Started          string `json:"started"`
+	TimeSpent        string `json:"timeSpent"`
+	TimeSpentSeconds int    `json:"timeSpentSeconds"`
+}
+
+// StartedAt parses the worklog start time
+func (w Worklog) StartedAt() (time.Time, error) {
+	return time.Parse(worklogTimeFormat, w.Started)
+}
+
+type WorklogsResponse struct {
+	StartAt    int       `json:"startAt"`
+	MaxResults int       `json:"maxResults"`
+	Total      int       `json:"total"`
+	Worklogs   []Worklog `json:"worklogs"`
+}
+
+// AddWorklog records time spent on an issue; comment may be nil
+func (c *Client) AddWorklog(ctx context.Context, issueKey string, spent time.Duration, started time.Time, comment *ADFNode) error {
+	payload := map[string]any{
+		"timeSpentSeconds": int(spent.Seconds()),
+		"started":          started.Format(worklogTimeFormat),
+	}
+	if comment != nil {
+		payload["comment"] = c.richText(ctx, comment)
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	u, err := c.baseURL.Parse(fmt.Sprintf("issue/%s/worklog", issueKey))
+	if err != nil {
+		return err
+	}
+
+	_, err = c.makeRequest(ctx, "POST", u.String(), bytes.NewReader(jsonData))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetWorklogs returns the worklogs of an issue started at or after since
+func (c *Client) GetWorklogs(ctx context.Context, issueKey string, since time.Time) ([]Worklog, error) {
+	var worklogs []Worklog
+	for startAt := 0; ; {
+		u, err := c.baseURL.Parse(fmt.Sprintf("issue/%s/worklog?startedAfter=%d&startAt=%d", issueKey, since.UnixMilli(), startAt))
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := c.makeRequest(ctx, "GET", u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page WorklogsResponse
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, err
+		}
+
+		worklogs = append(worklogs, page.Worklogs...)
+		startAt += len(page.Worklogs)
+		if len(page.Worklogs) == 0 || startAt >= page.Total {
+			return worklogs, nil
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/emilsto/jig/jira"
+)
+
+// resolveLinkType matches a phrase such as "blocks", "is blocked by" or "Relates" against the
+// instance's link types; reversed is true when the phrase is the inward description
+func resolveLinkType(types []jira.IssueLinkType, phrase string) (jira.IssueLinkType, bool, error) {
+	normalize := func(s string) string {
+		s = strings.NewReplacer("-", " ", "_", " ").Replace(strings.ToLower(s))
+		return strings.Join(strings.Fields(s), " ")
+	}
+	phrase = normalize(phrase)
+
+	for _, t := range types {
+		if normalize(t.Outward) == phrase || normalize(t.Name) == phrase {
+			return t, false, nil
+		}
+	}
+	for _, t := range types {
+		if normalize(t.Inward) == phrase {
+			return t, true, nil
+		}
+	}
+
+	var known []string
+	for _, t := range types {
+		known = append(known, fmt.Sprintf("%q", t.Outward), fmt.Sprintf("%q", t.Inward))
+	}
+	return jira.IssueLinkType{}, false, fmt.Errorf("unknown link type %q; try one of %s", phrase, strings.Join(known, ", "))
+}
+
+// runLinkCommand implements `jig link PROJ-1 blocks PROJ-2`
+func runLinkCommand(args []string) error {
+	if len(args) < 3 {
+		return fmt.Errorf("usage: jig link FROM <relation> TO, e.g. jig link PROJ-1 blocks PROJ-2")
+	}
+	from := strings.ToUpper(args[0])
+	to := strings.ToUpper(args[len(args)-1])
+	phrase := strings.Join(args[1:len(args)-1], " ")
+
+	_, client, err := loadClient()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	types, err := client.GetIssueLinkTypes(ctx)
+	if err != nil {
+		return err
+	}
+
+	linkType, reversed, err := resolveLinkType(types, phrase)
+	if err != nil {
+		return err
+	}
+
+	// "A is blocked by B" is stored as "B blocks A"
+	outwardFrom, outwardTo := from, to
+	if reversed {
+		outwardFrom, outwardTo = to, from
+	}
+
+	if err := client.CreateIssueLink(ctx, linkType.Name, outwardFrom, outwardTo); err != nil {
+		return err
+	}
+
+	printSuccess("%s %s %s", printHighlight(outwardFrom), linkType.Outward, printHighlight(outwardTo))
+	return nil
+}
+
+// runUnlinkCommand implements `jig unlink PROJ-1 PROJ-2`, removing every link between the two issues
+func runUnlinkCommand(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: jig unlink KEY OTHER-KEY")
+	}
+	issueKey := strings.ToUpper(args[0])
+	otherKey := strings.ToUpper(args[1])
+
+	_, client, err := loadClient()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	issue, err := client.GetIssueDetails(ctx, issueKey)
+	if err != nil {
+		return err
+	}
+
+	removed := 0
+	for _, link := range issue.Fields.IssueLinks {
+		other, relation := link.Other()
+		if other == nil || other.Key != otherKey {
+			continue
+		}
+		if err := client.DeleteIssueLink(ctx, link.ID); err != nil {
+			return err
+		}
+		printSuccess("Removed: %s %s %s", printHighlight(issueKey), relation, printHighlight(otherKey))
+		removed++
+	}
+
+	if removed == 0 {
+		return fmt.Errorf("%s is not linked to %s", issueKey, otherKey)
+	}
+	return nil
+}
+
+// printIssueLinks lists links grouped by relation, e.g. all "is blocked by" issues together
+func printIssueLinks(links []jira.IssueLink) {
+	var relations []string
+	grouped := map[string][]*jira.LinkedIssue{}
+	for _, link := range links {
+		other, relation := link.Other()
+		if other == nil {
+			continue
+		}
+		if _, ok := grouped[relation]; !ok {
+			relations = append(relations, relation)
+		}
+		grouped[relation] = append(grouped[relation], other)
+	}
+
+	if len(relations) == 0 {
+		return
+	}
+
+	fmt.Printf("\n  %sLinks:%s\n", colorDim, colorReset)
+	for _, relation := range relations {
+		fmt.Printf("    %s%s%s\n", colorBold, relation, colorReset)
+		for _, other := range grouped[relation] {
+			fmt.Printf("      %s%-12s%s %s %s\n",
+				colorCyan, other.Key, colorReset,
+				printStatus(fmt.Sprintf("%-15s", other.Fields.Status.Name), other.Fields.Status.StatusCategory.Key),
+				truncate(other.Fields.Summary, 60))
+		}
+	}
+}
+
+// isBlocked reports whether an issue is blocked by another issue that isn't done
+func isBlocked(issue jira.Issue) bool {
+	for _, link := range issue.Fields.IssueLinks {
+		if link.IsBlockedBy() {
+			return true
+		}
+	}
+	return false
+}
@@ -2,28 +2,36 @@ package main
 
 import (
 	"bufio"
-	"context"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 
 	"github.com/emilsto/jig/jira"
 )
 
 func main() {
+	// Configuration overrides such as --board work with every command, so take them out
+	// before the command and its own flags are parsed
+	args, err := extractGlobalFlags(os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+	os.Args = append(os.Args[:1], args...)
+
 	if handleCommandLine() {
 		return
 	}
 
 	// TOOO: Handle epics flag
-	helpFlag, _, oneshotFlag := parseFlags()
+	flags := parseFlags()
 
 	mainConfig, err := getOrCreateConfig("config.toml")
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	if helpFlag {
+	if flags.help {
 		printHelp()
 		return
 	}
@@ -32,43 +40,99 @@ func main() {
 		log.Fatalf("Failed to select project/board: %v", err)
 	}
 
-	jiraCfg := jira.Config{
-		BaseURL:  mainConfig.Api.Baseurl,
-		AgileURL: mainConfig.Api.Agileurl,
-		Email:    mainConfig.Api.Email,
-		APIKey:   mainConfig.Api.Apikey,
+	jiraClient, err := newJiraClient(mainConfig)
+	if err != nil {
+		log.Fatalf("Failed to create Jira client: %v", err)
 	}
 
-	jiraClient, err := jira.NewClient(jiraCfg)
+	fields := loadFieldMap(mainConfig, jiraClient)
+	where, err := namedFilter(mainConfig, flags.filter)
 	if err != nil {
-		log.Fatalf("Failed to create Jira client: %v", err)
+		log.Fatal(err)
+	}
+	filters, err := parseFieldFilters(append(where, flags.where...), fields)
+	if err != nil {
+		log.Fatal(err)
 	}
 
 	printDim("Using Project: %s (ID: %s), Board: %s (ID: %d)", project.Name, project.ID, board.Name, board.ID)
 	fmt.Println()
 
-	sprints, err := jiraClient.GetSprints(context.Background(), board.ID)
+	reader := bufio.NewReader(os.Stdin)
+	sprint, err := selectSprint(jiraClient, board, reader, flags.sprint)
 	if err != nil {
-		log.Fatalf("Failed to get sprints: %v", err)
+		log.Fatal(err)
 	}
 
-	if len(sprints) == 0 {
+	if sprint == nil {
 		fmt.Println("No active or future sprints found")
 		return
 	}
 
-	sprint := sprints[0]
-	printBold("Latest Sprint:")
-	fmt.Printf("  - ID: %d, Name: %s, State: %s\n\n", sprint.ID, printHighlight(sprint.Name), printStatus(sprint.State))
+	printBold("Sprint:")
+	fmt.Printf("  - ID: %d, Name: %s, State: %s\n\n", sprint.ID, printHighlight(sprint.Name), printSprintState(sprint.State))
 
 	ctx := &actionContext{
 		config:     mainConfig,
-		sprint:     sprint,
-		reader:     bufio.NewReader(os.Stdin),
-		oneshot:    oneshotFlag,
+		sprint:     *sprint,
+		reader:     reader,
+		oneshot:    flags.oneshot,
 		jiraClient: jiraClient,
 		board:      board,
+		project:    project,
+		fields:     fields,
+		filters:    filters,
+		categories: loadStatusCategories(),
 	}
 
 	runInteractiveLoop(ctx)
 }
+
+// newJiraClient builds a Jira API client from the loaded configuration
+func newJiraClient(config *Config) (*jira.Client, error) {
+	if config.tokenErr != nil {
+		return nil, config.tokenErr
+	}
+
+	cache, err := cacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	jiraCfg := jira.Config{
+		BaseURL:  config.Api.Baseurl,
+		AgileURL: config.Api.Agileurl,
+		Email:    config.Api.Email,
+		APIKey:   config.Api.Apikey,
+		Flavor:   jira.Flavor(config.Api.Flavor),
+		CacheDir: filepath.Join(cache, "api"),
+	}
+
+	auth := config.Api.Auth
+	if auth == "" && config.Api.Email == "" {
+		// A token without an account is a personal access token
+		auth = "pat"
+	}
+	switch auth {
+	case "", "basic":
+	case "pat":
+		jiraCfg.Auth = jira.BearerAuth{Token: config.Api.Apikey}
+	case "oauth":
+		auth, err := newOAuthAuth(config)
+		if err != nil {
+			return nil, err
+		}
+		jiraCfg.Auth = auth
+		jiraCfg.Flavor = jira.Cloud
+		jiraCfg.BaseURL, jiraCfg.AgileURL = jira.CloudAPIURLs(config.Api.CloudID)
+	default:
+		return nil, fmt.Errorf("unknown auth %q, expected basic, pat or oauth", config.Api.Auth)
+	}
+	switch jiraCfg.Flavor {
+	case "", jira.Cloud, jira.Server:
+	default:
+		return nil, fmt.Errorf("unknown flavor %q, expected cloud or server", config.Api.Flavor)
+	}
+
+	return jira.NewClient(jiraCfg)
+}
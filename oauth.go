@@ -0,0 +1,264 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emilsto/jig/jira"
+)
+
+// oauthCredentials is what the credential store holds for auth = "oauth"; the client
+// secret is kept with the tokens since refreshing needs it
+type oauthCredentials struct {
+	ClientSecret string          `json:"client_secret,omitempty"`
+	Token        jira.OAuthToken `json:"token"`
+}
+
+// newOAuthAuth builds the authenticator for auth = "oauth", saving refreshed tokens back
+// to the credential store. A token that isn't stored credentials, e.g. from JIG_API_TOKEN,
+// is used as a bare access token
+func newOAuthAuth(config *Config) (*jira.OAuthAuth, error) {
+	var creds oauthCredentials
+	if err := json.Unmarshal([]byte(config.Api.Apikey), &creds); err != nil {
+		creds = oauthCredentials{Token: jira.OAuthToken{AccessToken: config.Api.Apikey}}
+	}
+
+	store, err := newCredentialStore(config)
+	if err != nil {
+		return nil, err
+	}
+
+	auth := &jira.OAuthAuth{
+		Config: jira.OAuthConfig{ClientID: config.Api.OAuthClientID, ClientSecret: creds.ClientSecret},
+		Token:  creds.Token,
+	}
+	if store != nil {
+		account := credentialAccount(config)
+		auth.OnRefresh = func(token jira.OAuthToken) error {
+			creds.Token = token
+			data, err := json.Marshal(creds)
+			if err != nil {
+				return err
+			}
+			if err := store.set(account, string(data)); err != nil {
+				return fmt.Errorf("failed to save refreshed OAuth token to %s: %v", store.name(), err)
+			}
+			return nil
+		}
+	}
+	return auth, nil
+}
+
+// authLoginOAuth implements `jig auth login -oauth`: the authorization code flow with PKCE,
+// receiving the code on a localhost callback
+func authLoginOAuth(config *Config, storeName, clientID string, port int) error {
+	stored := storedAPI(config)
+	if clientID == "" {
+		clientID = stored.OAuthClientID
+	}
+	if clientID == "" {
+		return fmt.Errorf("no OAuth client ID; create an OAuth 2.0 app at https://developer.atlassian.com/console/myapps " +
+			"with callback URL http://localhost:8085/callback and pass -client-id")
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	secret := os.Getenv("JIG_OAUTH_CLIENT_SECRET")
+	if secret == "" {
+		var err error
+		if secret, err = readSecret(reader, "OAuth client secret"); err != nil {
+			return err
+		}
+	}
+
+	oauth := jira.OAuthConfig{
+		ClientID:     clientID,
+		ClientSecret: secret,
+		RedirectURL:  fmt.Sprintf("http://localhost:%d/callback", port),
+		Scopes:       jira.OAuthScopes,
+	}
+	code, verifier, err := receiveAuthCode(oauth, port)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	token, err := oauth.Exchange(ctx, code, verifier)
+	if err != nil {
+		return fmt.Errorf("failed to exchange the authorization code: %v", err)
+	}
+
+	resource, err := pickCloudResource(ctx, reader, token.AccessToken, config.Api.Baseurl)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(oauthCredentials{ClientSecret: secret, Token: *token})
+	if err != nil {
+		return err
+	}
+	config.Api.Apikey = string(data)
+	config.Api.Auth = "oauth"
+	config.Api.OAuthClientID = clientID
+	config.Api.CloudID = resource.ID
+	// Requests go through api.atlassian.com, but the site URL still names the account
+	config.Api.Baseurl = resource.URL + "/rest/api/3"
+	config.Api.Agileurl = resource.URL + "/rest/agile/1.0"
+	config.tokenErr = nil
+
+	client, err := newJiraClient(config)
+	if err != nil {
+		return err
+	}
+	if err := client.VerifyCredentials(ctx); err != nil {
+		return fmt.Errorf("OAuth token rejected by %s: %v", resource.URL, err)
+	}
+
+	store, err := storeAPIToken(config, storeName)
+	if err != nil {
+		return fmt.Errorf("OAuth tokens need a credential store: %v", err)
+	}
+	stored.Auth = "oauth"
+	stored.OAuthClientID = clientID
+	stored.CloudID = resource.ID
+	stored.CredentialStore = store.name()
+	stored.Baseurl = config.Api.Baseurl
+	stored.Agileurl = config.Api.Agileurl
+	if err := saveConfig(config, config.path); err != nil {
+		return err
+	}
+
+	printSuccess("Logged in to %s with OAuth; tokens saved to %s", printHighlight(resource.Name), store.name())
+	return nil
+}
+
+// receiveAuthCode sends the user to the consent page and waits for the authorization code
+// on the localhost callback
+func receiveAuthCode(oauth jira.OAuthConfig, port int) (string, string, error) {
+	verifier := randomToken()
+	state := randomToken()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:"+strconv.Itoa(port))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to listen for the OAuth callback: %v", err)
+	}
+
+	type callback struct {
+		code string
+		err  error
+	}
+	results := make(chan callback, 1)
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/callback" {
+			http.NotFound(w, r)
+			return
+		}
+
+		query := r.URL.Query()
+		var result callback
+		switch {
+		case query.Get("state") != state:
+			result.err = fmt.Errorf("OAuth callback with an unexpected state")
+		case query.Get("error") != "":
+			result.err = fmt.Errorf("authorization denied: %s", query.Get("error_description"))
+		default:
+			result.code = query.Get("code")
+		}
+
+		if result.err != nil {
+			http.Error(w, result.err.Error(), http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(w, "jig is logged in; you can close this tab.")
+		}
+		select {
+		case results <- result:
+		default:
+		}
+	})}
+	go server.Serve(listener)
+	defer server.Close()
+
+	authURL := oauth.AuthCodeURL(state, verifier)
+	printInfo("Opening the browser to log in. If it doesn't open, visit:")
+	fmt.Println(authURL)
+	openBrowser(authURL)
+
+	select {
+	case result := <-results:
+		return result.code, verifier, result.err
+	case <-time.After(5 * time.Minute):
+		return "", "", fmt.Errorf("timed out waiting for the OAuth callback")
+	}
+}
+
+// pickCloudResource picks the site to use from those the token was granted: the one
+// matching the configured base URL, the only one, or the one the user selects
+func pickCloudResource(ctx context.Context, reader *bufio.Reader, accessToken, baseURL string) (*jira.CloudResource, error) {
+	resources, err := jira.GetAccessibleResources(ctx, accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accessible sites: %v", err)
+	}
+	if len(resources) == 0 {
+		return nil, fmt.Errorf("the OAuth app wasn't granted access to any Jira site")
+	}
+
+	if base, err := url.Parse(baseURL); err == nil && base.Host != "" {
+		for i, resource := range resources {
+			if site, err := url.Parse(resource.URL); err == nil && strings.EqualFold(site.Host, base.Host) {
+				return &resources[i], nil
+			}
+		}
+		printWarning("The OAuth app has no access to %s", base.Host)
+	}
+	if len(resources) == 1 {
+		return &resources[0], nil
+	}
+
+	fmt.Println()
+	printBold("Sites:")
+	for i, resource := range resources {
+		fmt.Printf("  %d. %s %s%s%s\n", i+1, resource.Name, colorDim, resource.URL, colorReset)
+	}
+	printPrompt("Select site (number)")
+	input, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	selection, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil || selection < 1 || selection > len(resources) {
+		return nil, fmt.Errorf("invalid selection")
+	}
+	return &resources[selection-1], nil
+}
+
+func randomToken() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// openBrowser opens link in the default browser, if there is one
+func openBrowser(link string) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", link)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", link)
+	default:
+		cmd = exec.Command("xdg-open", link)
+	}
+	cmd.Start()
+}
@@ -50,8 +50,20 @@ func printHighlight(text string) string {
 	return fmt.Sprintf("%s%s%s", colorCyan, text, colorReset)
 }
 
-func printStatus(text string) string {
-	return fmt.Sprintf("%s%s%s", colorYellow, text, colorReset)
+// printStatus colours a status by its category key: new, indeterminate (in progress) or done
+func printStatus(text, category string) string {
+	return fmt.Sprintf("%s%s%s", statusColor(category), text, colorReset)
+}
+
+func statusColor(category string) string {
+	switch category {
+	case "new":
+		return colorBlue
+	case "done":
+		return colorGreen
+	default:
+		return colorYellow
+	}
 }
 
 func printBold(format string, args ...any) {
@@ -59,41 +71,65 @@ func printBold(format string, args ...any) {
 	fmt.Printf("%s%s%s\n", colorBold, msg, colorReset)
 }
 
-func printTableRow(num int, key, summary, status, assignee string, maxSummaryLen int) {
+func printTableRow(num int, key, summary string, status jira.Status, assignee string, maxSummaryLen int, extra []string) {
 	// Truncate summary if too long
 	if len(summary) > maxSummaryLen {
 		summary = summary[:maxSummaryLen-3] + "..."
 	}
 
-	fmt.Printf("%s%3d%s │ %s%-20s%s │ %-*s │ %s%-15s%s │ %s\n",
+	fmt.Printf("%s%3d%s │ %s%-20s%s │ %-*s │ %s%-15s%s │ %-20s",
 		colorDim, num, colorReset,
 		colorCyan, key, colorReset,
 		maxSummaryLen, summary,
-		colorYellow, status, colorReset,
-		assignee)
+		statusColor(status.StatusCategory.Key), truncate(status.Name, 15), colorReset,
+		truncate(assignee, 20))
+	for _, value := range extra {
+		fmt.Printf(" │ %-*s", extraColumnWidth, truncate(value, extraColumnWidth))
+	}
+	fmt.Println()
 }
 
-func printTableHeader(maxSummaryLen int) {
-	fmt.Printf("%s%3s%s │ %s%-20s%s │ %-*s │ %s%-15s%s │ %s\n",
+func printTableHeader(maxSummaryLen int, extraHeaders []string) {
+	fmt.Printf("%s%3s%s │ %s%-20s%s │ %-*s │ %s%-15s%s │ %s%-20s%s",
 		colorBold, "#", colorReset,
 		colorBold, "KEY", colorReset,
 		maxSummaryLen, "SUMMARY",
 		colorBold, "STATUS", colorReset,
-		"ASSIGNEE")
+		colorBold, "ASSIGNEE", colorReset)
+	for _, header := range extraHeaders {
+		fmt.Printf(" │ %s%-*s%s", colorBold, extraColumnWidth, truncate(header, extraColumnWidth), colorReset)
+	}
+	fmt.Println()
 
 	// Print separator line
-	fmt.Printf("────┼──────────────────────┼─%s─┼─────────────────┼─%s\n",
+	fmt.Printf("────┼──────────────────────┼─%s─┼─────────────────┼─%s",
 		strings.Repeat("─", maxSummaryLen),
-		strings.Repeat("─", 30))
+		strings.Repeat("─", 20))
+	for range extraHeaders {
+		fmt.Printf("─┼─%s", strings.Repeat("─", extraColumnWidth))
+	}
+	fmt.Println()
+}
+
+// extraColumnWidth is the width of custom field columns in the issue table
+const extraColumnWidth = 14
+
+// truncate shortens text to width runes, marking the cut with an ellipsis
+func truncate(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	return string(runes[:width-1]) + "…"
 }
 
-func printIssueDetails(issue *jira.DetailedIssue, extractDesc func(any) string) {
+func printIssueDetails(issue *jira.DetailedIssue, extractDesc func(any) string, fields fieldMap) {
 	fmt.Println()
 	printBold("Issue Details:")
 	fmt.Printf("  %sKey:%s          %s\n", colorDim, colorReset, printHighlight(issue.Key))
 	fmt.Printf("  %sSummary:%s      %s\n", colorDim, colorReset, issue.Fields.Summary)
 	fmt.Printf("  %sType:%s         %s\n", colorDim, colorReset, issue.Fields.IssueType.Name)
-	fmt.Printf("  %sStatus:%s       %s\n", colorDim, colorReset, printStatus(issue.Fields.Status.Name))
+	fmt.Printf("  %sStatus:%s       %s\n", colorDim, colorReset, printStatus(issue.Fields.Status.Name, issue.Fields.Status.StatusCategory.Key))
 
 	assignee := issue.Fields.Assignee.DisplayName
 	if assignee == "" {
@@ -111,15 +147,33 @@ func printIssueDetails(issue *jira.DetailedIssue, extractDesc func(any) string)
 		fmt.Printf("  %sLabels:%s       %s\n", colorDim, colorReset, strings.Join(issue.Fields.Labels, ", "))
 	}
 
+	for _, name := range fields.names() {
+		value := formatFieldValue(issue.CustomFields[fields[name]])
+		if value == "" {
+			continue
+		}
+		fmt.Printf("  %s%-13s%s %s\n", colorDim, fieldLabel(name)+":", colorReset, value)
+	}
+
+	printIssueLinks(issue.Fields.IssueLinks)
+
+	if len(issue.Fields.Attachment) > 0 {
+		fmt.Printf("\n  %sAttachments:%s\n", colorDim, colorReset)
+		for _, a := range issue.Fields.Attachment {
+			fmt.Printf("    %s %s(%s)%s\n", a.Filename, colorDim, formatSize(a.Size), colorReset)
+		}
+	}
+
 	description := extractDesc(issue.Fields.Description)
 	if description != "" {
 		fmt.Printf("\n  %sDescription:%s\n", colorDim, colorReset)
-		// Wrap description text
 		descLines := strings.Split(description, "\n")
 		for _, line := range descLines {
-			if line != "" {
-				fmt.Printf("    %s\n", line)
+			if line == "" {
+				fmt.Println()
+				continue
 			}
+			fmt.Printf("    %s\n", line)
 		}
 	}
 	fmt.Println()
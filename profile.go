@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+const profileUsage = "usage: jig profile list | add NAME | use NAME|none"
+
+// runProfileCommand implements `jig profile list|add|use`
+func runProfileCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf(profileUsage)
+	}
+	if findConfig("config.toml") == "" {
+		return fmt.Errorf("no config.toml found; run jig once to create one")
+	}
+
+	config, err := getOrCreateConfig("config.toml")
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case (args[0] == "list" || args[0] == "ls") && len(args) == 1:
+		return listProfiles(config)
+	case args[0] == "add" && len(args) == 2:
+		return addProfile(config, args[1])
+	case args[0] == "use" && len(args) == 2:
+		return useProfile(config, args[1])
+	default:
+		return fmt.Errorf(profileUsage)
+	}
+}
+
+func listProfiles(config *Config) error {
+	fmt.Println()
+	printBold("Profiles:")
+	if len(config.Profiles) == 0 {
+		printDim("  none; add one with jig profile add NAME")
+		return nil
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(config.Profiles)) {
+		marker := " "
+		if name == config.activeProfile {
+			marker = colorGreen + "*" + colorReset
+		}
+		line := fmt.Sprintf("  %s %-16s %s", marker, printHighlight(name), config.Profiles[name].Api.Baseurl)
+		if name == config.Profile {
+			line += fmt.Sprintf("  %s(default)%s", colorDim, colorReset)
+		}
+		fmt.Println(line)
+	}
+
+	fmt.Println()
+	if config.activeProfile == "" {
+		printDim("Using the top-level [api] settings")
+	} else {
+		printDim("Using %s, from %s", config.activeProfile, config.origins["profile"])
+	}
+	return nil
+}
+
+// addProfile asks for a site, account, projects and boards like the first run does and
+// saves them as [profiles.NAME]
+func addProfile(config *Config, name string) error {
+	if _, ok := config.Profiles[name]; ok {
+		return fmt.Errorf("profile %q already exists", name)
+	}
+
+	printInfo("Adding profile %s", printHighlight(name))
+	fmt.Println()
+	added, err := promptForConfig()
+	if err != nil {
+		return err
+	}
+
+	if config.file.Profiles == nil {
+		config.file.Profiles = map[string]*Profile{}
+	}
+	config.file.Profiles[name] = &Profile{
+		Api:      added.Api,
+		Git:      added.Git,
+		Projects: added.Projects,
+		Fields:   added.Fields,
+	}
+	if err := saveConfig(config, config.path); err != nil {
+		return err
+	}
+
+	fmt.Println()
+	printSuccess("Added profile %s to %s", printHighlight(name), config.path)
+	printDim("Select it with --profile %s, JIG_PROFILE=%s, profile = %q in .jigrc, or jig profile use %s", name, name, name, name)
+	return nil
+}
+
+// useProfile sets the profile used when nothing else selects one; "none" goes back to the top-level settings
+func useProfile(config *Config, name string) error {
+	if name == "none" {
+		name = ""
+	} else if _, ok := config.Profiles[name]; !ok {
+		return fmt.Errorf("unknown profile %q; see jig profile list", name)
+	}
+
+	config.file.Profile = name
+	if err := saveConfig(config, config.path); err != nil {
+		return err
+	}
+
+	if name == "" {
+		printSuccess("Using the top-level settings by default")
+	} else {
+		printSuccess("Using profile %s by default", printHighlight(name))
+	}
+	if origin := config.origins["profile"]; origin != "" && origin != config.path {
+		printWarning("%s still selects profile %s here", origin, config.activeProfile)
+	}
+	return nil
+}
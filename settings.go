@@ -0,0 +1,339 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// configSetting is a value that can come from a command-line flag, the environment,
+// .jigrc or config.toml, in that order of precedence
+type configSetting struct {
+	name string
+	env  string
+	flag string
+}
+
+var configSettings = []configSetting{
+	{name: "api.baseurl", env: "JIG_API_BASEURL", flag: "baseurl"},
+	{name: "api.agileurl", env: "JIG_API_AGILEURL", flag: "agileurl"},
+	{name: "api.email", env: "JIG_API_EMAIL", flag: "email"},
+	{name: "api.token", env: "JIG_API_TOKEN", flag: "token"},
+	{name: "project", env: "JIG_PROJECT", flag: "project"},
+	{name: "board", env: "JIG_BOARD", flag: "board"},
+	{name: "git.branchbase", env: "JIG_GIT_BRANCHBASE", flag: "branchbase"},
+	{name: "profile", env: "JIG_PROFILE", flag: "profile"},
+}
+
+// cliOverrides holds the global --flag values given on the command line, keyed by flag name
+var cliOverrides = map[string]string{}
+
+// configFlag is the config file given with --config, which replaces the search for config.toml
+var configFlag string
+
+// extractGlobalFlags removes the configuration flags (--email x, --board=12, ...) given
+// before the command, so they work with every command. It stops at the first argument
+// that isn't a flag, or after --, so the command's own arguments are left alone
+func extractGlobalFlags(args []string) ([]string, error) {
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--" || !strings.HasPrefix(args[i], "-") {
+			return append(rest, args[i:]...), nil
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(args[i], "-"), "=")
+		isSetting := slices.ContainsFunc(configSettings, func(s configSetting) bool { return s.flag == name })
+		if !isSetting && name != "config" {
+			rest = append(rest, args[i])
+			continue
+		}
+
+		if !hasValue {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("flag --%s needs a value", name)
+			}
+			i++
+			value = args[i]
+		}
+		if name == "config" {
+			configFlag = value
+			continue
+		}
+		cliOverrides[name] = value
+	}
+	return rest, nil
+}
+
+// settingValue returns the config field a setting is stored in
+func settingValue(config *Config, name string) *string {
+	switch name {
+	case "api.baseurl":
+		return &config.Api.Baseurl
+	case "api.agileurl":
+		return &config.Api.Agileurl
+	case "api.email":
+		return &config.Api.Email
+	case "api.token":
+		return &config.Api.Apikey
+	case "project":
+		return &config.project
+	case "board":
+		return &config.board
+	case "git.branchbase":
+		return &config.Git.Branchbase
+	case "profile":
+		return &config.activeProfile
+	}
+	return nil
+}
+
+// resolveConfig layers the selected profile, .jigrc, environment variables and flags over
+// the values loaded from config.toml and records where each value came from in config.origins
+func resolveConfig(config *Config, configPath string) error {
+	config.activeProfile = config.Profile
+	config.origins = map[string]string{}
+	for _, s := range configSettings {
+		if *settingValue(config, s.name) != "" {
+			config.origins[s.name] = configPath
+		}
+	}
+
+	jigrc, jigrcOrigins, err := loadMergedJigRC()
+	if err != nil {
+		return err
+	}
+	config.jigrcs = findJigRCs()
+
+	// The profile comes first as everything else is layered over it
+	if jigrc != nil && jigrc.Profile != "" {
+		config.activeProfile = jigrc.Profile
+		config.origins["profile"] = jigrcOrigins["profile"]
+	}
+	applyOverrides(config, "profile")
+	if config.activeProfile != "" {
+		if err := applyProfile(config, config.activeProfile); err != nil {
+			return err
+		}
+	}
+
+	if jigrc != nil {
+		project := jigrc.ProjectID
+		if project == "" {
+			project = jigrc.ProjectName
+		}
+		board := jigrc.BoardName
+		if jigrc.BoardID != 0 {
+			board = strconv.Itoa(jigrc.BoardID)
+		}
+		for name, value := range map[string]string{"project": project, "board": board} {
+			if value != "" {
+				*settingValue(config, name) = value
+				config.origins[name] = jigrcOrigins[name+"_id"]
+				if config.origins[name] == "" {
+					config.origins[name] = jigrcOrigins[name+"_name"]
+				}
+			}
+		}
+		applyJigRC(config, jigrc, jigrcOrigins)
+	}
+
+	for _, s := range configSettings {
+		applyOverrides(config, s.name)
+	}
+
+	// An overridden base URL points at another site, so derive the agile URL from it
+	// unless that was overridden too
+	baseOverridden := isOverride(config.origins["api.baseurl"])
+	if config.Api.Baseurl != "" && (config.Api.Agileurl == "" || baseOverridden && !isOverride(config.origins["api.agileurl"])) {
+		if site, _, ok := strings.Cut(config.Api.Baseurl, "/rest/api/"); ok {
+			config.Api.Agileurl = site + "/rest/agile/1.0"
+			config.origins["api.agileurl"] = "derived from api.baseurl"
+		}
+	}
+
+	// A token from the environment or a flag wins over the credential store. A store that
+	// can't be read isn't fatal here so that jig auth login can still fix it
+	if !isOverride(config.origins["api.token"]) {
+		store, err := newCredentialStore(config)
+		if err != nil {
+			return err
+		}
+		if store != nil {
+			config.tokenErr = resolveAPIToken(config)
+			config.origins["api.token"] = store.name()
+		}
+	}
+	return nil
+}
+
+// applyOverrides sets a setting from its environment variable and then its flag, when given
+func applyOverrides(config *Config, name string) {
+	i := slices.IndexFunc(configSettings, func(s configSetting) bool { return s.name == name })
+	s := configSettings[i]
+	if value := os.Getenv(s.env); value != "" {
+		*settingValue(config, s.name) = value
+		config.origins[s.name] = "$" + s.env
+	}
+	if value, ok := cliOverrides[s.flag]; ok {
+		*settingValue(config, s.name) = value
+		config.origins[s.name] = "--" + s.flag
+	}
+}
+
+// applyProfile replaces the top-level settings with those of a profile. Its [api] section
+// is taken as a whole since it describes another site; the rest only where it is set
+func applyProfile(config *Config, name string) error {
+	profile, ok := config.Profiles[name]
+	if !ok {
+		return fmt.Errorf("unknown profile %q; see jig profile list", name)
+	}
+
+	origin := "profile " + name
+	config.Api = profile.Api
+	for _, s := range configSettings {
+		if !strings.HasPrefix(s.name, "api.") {
+			continue
+		}
+		if *settingValue(config, s.name) != "" {
+			config.origins[s.name] = origin
+		} else {
+			delete(config.origins, s.name)
+		}
+	}
+
+	if profile.Git != (GitConfig{}) {
+		config.Git = profile.Git
+		config.origins["git.branchbase"] = origin
+	}
+	if len(profile.Projects) > 0 {
+		config.Projects = profile.Projects
+	}
+	if profile.Fields != nil {
+		config.Fields = profile.Fields
+	}
+	return nil
+}
+
+// applyJigRC layers the team conventions of the merged .jigrc over config.toml: values it
+// sets replace those from config.toml, and its named filters are added to (or replace) the
+// ones there. origins are the files the keys came from, see loadMergedJigRC
+func applyJigRC(config *Config, jigrc *JigRC, origins map[string]string) {
+	set := func(key string, apply func()) {
+		if origin, ok := origins[key]; ok {
+			apply()
+			config.origins[key] = origin
+		}
+	}
+	set("git.branchbase", func() { config.Git.Branchbase = jigrc.Git.Branchbase })
+	set("git.branch_template", func() { config.Git.BranchTemplate = jigrc.Git.BranchTemplate })
+	set("git.commit_format", func() { config.Git.CommitFormat = jigrc.Git.CommitFormat })
+	set("start_status", func() { config.StartStatus = jigrc.StartStatus })
+	set("issue_types", func() { config.IssueTypes = jigrc.IssueTypes })
+	set("table.columns", func() { config.Table.Columns = jigrc.Table.Columns })
+
+	if len(jigrc.Filters) > 0 {
+		filters := maps.Clone(config.Filters)
+		if filters == nil {
+			filters = map[string][]string{}
+		}
+		for name, where := range jigrc.Filters {
+			filters[name] = where
+			config.origins["filters."+name] = origins["filters."+name]
+		}
+		config.Filters = filters
+	}
+}
+
+// storedAPI returns the [api] section of config.toml that the active profile was read from,
+// so changes to it are saved in the right place
+func storedAPI(config *Config) *APIConfig {
+	if config.file == nil {
+		return &config.Api
+	}
+	if profile := config.file.Profiles[config.activeProfile]; profile != nil {
+		return &profile.Api
+	}
+	return &config.file.Api
+}
+
+// storedGit returns the [git] section of config.toml that the active profile uses
+func storedGit(config *Config) *GitConfig {
+	if config.file == nil {
+		return &config.Git
+	}
+	if profile := config.file.Profiles[config.activeProfile]; profile != nil {
+		return &profile.Git
+	}
+	return &config.file.Git
+}
+
+// storedProjects returns the projects of config.toml that the active profile uses
+func storedProjects(config *Config) *[]Project {
+	if config.file == nil {
+		return &config.Projects
+	}
+	if profile := config.file.Profiles[config.activeProfile]; profile != nil {
+		return &profile.Projects
+	}
+	return &config.file.Projects
+}
+
+// storedFields returns the field mappings of config.toml that the active profile uses
+func storedFields(config *Config) map[string]string {
+	fields := &config.Fields
+	if config.file != nil {
+		fields = &config.file.Fields
+		if profile := config.file.Profiles[config.activeProfile]; profile != nil {
+			fields = &profile.Fields
+		}
+	}
+	if *fields == nil {
+		*fields = map[string]string{}
+	}
+	return *fields
+}
+
+// isOverride reports whether an origin is the environment or a flag
+func isOverride(origin string) bool {
+	return strings.HasPrefix(origin, "$") || strings.HasPrefix(origin, "--")
+}
+
+// hasAPIOverrides reports whether the environment or flags name a Jira site and token,
+// so jig can run without a config.toml, e.g. in CI. The email may be left out for a
+// personal access token
+func hasAPIOverrides() bool {
+	for _, name := range []string{"api.baseurl", "api.token"} {
+		i := slices.IndexFunc(configSettings, func(s configSetting) bool { return s.name == name })
+		if os.Getenv(configSettings[i].env) == "" && cliOverrides[configSettings[i].flag] == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// runConfigSources implements `jig config sources`, listing each setting's value and origin
+func runConfigSources() error {
+	config, err := getOrCreateConfig("config.toml")
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	printBold("Settings (flag > env > .jigrc > profile > config.toml):")
+	for _, s := range configSettings {
+		value := *settingValue(config, s.name)
+		if s.name == "api.token" && value != "" {
+			value = "********"
+		}
+		origin := config.origins[s.name]
+		if origin == "" {
+			origin = "not set"
+		}
+		fmt.Printf("  %-16s %-45s %s%s%s\n", s.name, truncate(value, 45), colorDim, origin, colorReset)
+	}
+	fmt.Println()
+	printDim("Override with --<flag> or the environment, e.g. --board 12 or JIG_BOARD=12")
+	return nil
+}
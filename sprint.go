@@ -0,0 +1,357 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emilsto/jig/jira"
+)
+
+const sprintUsage = "usage: jig sprint list | show [SPRINT] | move KEY... TARGET | start [SPRINT] | close [SPRINT]"
+
+// findSprint resolves a sprint reference: an ID, a (partial) name, or one of current, next and previous
+func findSprint(sprints []jira.Sprint, ref string) (*jira.Sprint, error) {
+	switch strings.ToLower(ref) {
+	case "current", "active":
+		for i := range sprints {
+			if sprints[i].State == "active" {
+				return &sprints[i], nil
+			}
+		}
+		return nil, fmt.Errorf("no active sprint")
+	case "next":
+		for i := range sprints {
+			if sprints[i].State == "future" {
+				return &sprints[i], nil
+			}
+		}
+		return nil, fmt.Errorf("no future sprint")
+	case "previous", "last":
+		var previous *jira.Sprint
+		for i := range sprints {
+			if sprints[i].State == "closed" && (previous == nil || sprints[i].CompleteDate > previous.CompleteDate) {
+				previous = &sprints[i]
+			}
+		}
+		if previous == nil {
+			return nil, fmt.Errorf("no closed sprint")
+		}
+		return previous, nil
+	}
+
+	if id, err := strconv.Atoi(ref); err == nil {
+		for i := range sprints {
+			if sprints[i].ID == id {
+				return &sprints[i], nil
+			}
+		}
+	}
+
+	var matches []*jira.Sprint
+	for i := range sprints {
+		if strings.EqualFold(sprints[i].Name, ref) {
+			return &sprints[i], nil
+		}
+		if strings.Contains(strings.ToLower(sprints[i].Name), strings.ToLower(ref)) {
+			matches = append(matches, &sprints[i])
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no sprint matching %q", ref)
+	case 1:
+		return matches[0], nil
+	default:
+		names := make([]string, len(matches))
+		for i, sprint := range matches {
+			names[i] = fmt.Sprintf("%q", sprint.Name)
+		}
+		return nil, fmt.Errorf("%q matches several sprints: %s", ref, strings.Join(names, ", "))
+	}
+}
+
+func printSprintState(state string) string {
+	padded := fmt.Sprintf("%-7s", state)
+	switch state {
+	case "active":
+		return colorGreen + padded + colorReset
+	case "future":
+		return colorBlue + padded + colorReset
+	default:
+		return colorDim + padded + colorReset
+	}
+}
+
+// sprintDates renders a sprint's date range, e.g. "2024-03-04 → 2024-03-18"
+func sprintDates(sprint jira.Sprint) string {
+	if sprint.StartDate == "" {
+		return ""
+	}
+	return formatDate(sprint.StartDate) + " → " + formatDate(sprint.EndDate)
+}
+
+// runSprintCommand implements `jig sprint ...` against the board of the current directory
+func runSprintCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf(sprintUsage)
+	}
+
+	config, client, err := loadClient()
+	if err != nil {
+		return err
+	}
+
+	_, board, err := selectProjectAndBoard(config)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	sprints, err := client.GetBoardSprints(ctx, board.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get sprints: %v", err)
+	}
+
+	switch args[0] {
+	case "list", "ls":
+		return listSprints(sprints, args[1:])
+	case "show":
+		return showSprint(client, sprints, args[1:])
+	case "move", "mv":
+		return moveToSprint(client, sprints, args[1:])
+	case "start":
+		return startSprint(client, sprints, args[1:])
+	case "close":
+		return closeSprint(client, sprints, args[1:])
+	default:
+		return fmt.Errorf("unknown sprint command %q; %s", args[0], sprintUsage)
+	}
+}
+
+func listSprints(sprints []jira.Sprint, args []string) error {
+	fs := flag.NewFlagSet("sprint list", flag.ExitOnError)
+	open := fs.Bool("open", false, "Hide closed sprints")
+	fs.Parse(args)
+
+	fmt.Println()
+	printBold("Sprints:")
+	for _, sprint := range sprints {
+		if *open && sprint.State == "closed" {
+			continue
+		}
+		fmt.Printf("  %s%6d%s  %s  %-30s %s%s%s\n",
+			colorCyan, sprint.ID, colorReset, printSprintState(sprint.State),
+			truncate(sprint.Name, 30), colorDim, sprintDates(sprint), colorReset)
+		if sprint.Goal != "" && sprint.State != "closed" {
+			printDim("                  %s", truncate(sprint.Goal, 70))
+		}
+	}
+	return nil
+}
+
+func showSprint(client *jira.Client, sprints []jira.Sprint, args []string) error {
+	ref := "current"
+	if len(args) > 0 {
+		ref = strings.Join(args, " ")
+	}
+	sprint, err := findSprint(sprints, ref)
+	if err != nil {
+		return err
+	}
+
+	issues, err := client.GetAllSprintIssues(context.Background(), sprint.ID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	printBold("%s (%s, %d issues)", sprint.Name, sprint.State, len(issues))
+	if dates := sprintDates(*sprint); dates != "" {
+		printDim("  %s", dates)
+	}
+	if sprint.Goal != "" {
+		printDim("  Goal: %s", sprint.Goal)
+	}
+	fmt.Println()
+	printSprintIssues(issues)
+	return nil
+}
+
+func printSprintIssues(issues []jira.Issue) {
+	for _, issue := range issues {
+		assignee := issue.Fields.Assignee.DisplayName
+		if assignee == "" {
+			assignee = "Unassigned"
+		}
+		fmt.Printf("  %s%-12s%s %s %-50s %s%s%s\n",
+			colorCyan, issue.Key, colorReset,
+			printStatus(fmt.Sprintf("%-15s", truncate(issue.Fields.Status.Name, 15)), issue.Fields.Status.StatusCategory.Key),
+			truncate(issue.Fields.Summary, 50), colorDim, assignee, colorReset)
+	}
+}
+
+// moveToSprint implements `jig sprint move KEY... TARGET`, where TARGET is a sprint or "backlog"
+func moveToSprint(client *jira.Client, sprints []jira.Sprint, args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("usage: jig sprint move KEY... next|backlog|SPRINT")
+	}
+
+	target := args[len(args)-1]
+	keys := make([]string, len(args)-1)
+	for i, key := range args[:len(args)-1] {
+		keys[i] = strings.ToUpper(key)
+	}
+
+	ctx := context.Background()
+	if strings.EqualFold(target, "backlog") {
+		if err := client.MoveIssuesToBacklog(ctx, keys); err != nil {
+			return err
+		}
+		printSuccess("Moved %s to the backlog", printHighlight(strings.Join(keys, ", ")))
+		return nil
+	}
+
+	sprint, err := findSprint(sprints, target)
+	if err != nil {
+		return err
+	}
+	if sprint.State == "closed" {
+		return fmt.Errorf("%s is closed", sprint.Name)
+	}
+
+	if err := client.MoveIssuesToSprint(ctx, sprint.ID, keys); err != nil {
+		return err
+	}
+	printSuccess("Moved %s to %s", printHighlight(strings.Join(keys, ", ")), sprint.Name)
+	return nil
+}
+
+// startSprint implements `jig sprint start [SPRINT] [-days N] [-goal text]`, defaulting to the next sprint
+func startSprint(client *jira.Client, sprints []jira.Sprint, args []string) error {
+	fs := flag.NewFlagSet("sprint start", flag.ExitOnError)
+	days := fs.Int("days", 14, "Sprint length in days")
+	goal := fs.String("goal", "", "Sprint goal")
+	ref, err := parseSprintRef(fs, args, "next")
+	if err != nil {
+		return err
+	}
+
+	sprint, err := findSprint(sprints, ref)
+	if err != nil {
+		return err
+	}
+	if sprint.State != "future" {
+		return fmt.Errorf("%s is %s; only future sprints can be started", sprint.Name, sprint.State)
+	}
+
+	start := time.Now()
+	end := start.AddDate(0, 0, *days)
+	if err := client.StartSprint(context.Background(), sprint.ID, start, end, *goal); err != nil {
+		return err
+	}
+
+	printSuccess("Started %s, ends %s", printHighlight(sprint.Name), end.Format("Mon Jan 2"))
+	return nil
+}
+
+// closeSprint implements `jig sprint close [SPRINT] [-move next|backlog|SPRINT]`, summarising
+// incomplete issues and optionally carrying them over before the sprint is closed
+func closeSprint(client *jira.Client, sprints []jira.Sprint, args []string) error {
+	fs := flag.NewFlagSet("sprint close", flag.ExitOnError)
+	moveTo := fs.String("move", "", "Move incomplete issues to next, backlog or another sprint")
+	yes := fs.Bool("y", false, "Don't ask for confirmation")
+	ref, err := parseSprintRef(fs, args, "current")
+	if err != nil {
+		return err
+	}
+
+	sprint, err := findSprint(sprints, ref)
+	if err != nil {
+		return err
+	}
+	if sprint.State != "active" {
+		return fmt.Errorf("%s is %s; only active sprints can be closed", sprint.Name, sprint.State)
+	}
+
+	ctx := context.Background()
+	issues, err := client.GetAllSprintIssues(ctx, sprint.ID)
+	if err != nil {
+		return err
+	}
+
+	var incomplete []jira.Issue
+	for _, issue := range issues {
+		if !isDone(issue) {
+			incomplete = append(incomplete, issue)
+		}
+	}
+
+	fmt.Println()
+	printBold("%s: %d of %d issues done", sprint.Name, len(issues)-len(incomplete), len(issues))
+	if len(incomplete) > 0 {
+		printWarning("%d incomplete:", len(incomplete))
+		printSprintIssues(incomplete)
+	}
+	fmt.Println()
+
+	if !*yes {
+		ok, err := confirm(bufio.NewReader(os.Stdin), fmt.Sprintf("Close %s?", sprint.Name), false)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Cancelled")
+			return nil
+		}
+	}
+
+	if *moveTo != "" && len(incomplete) > 0 {
+		var keys []string
+		for _, issue := range incomplete {
+			// Sub-tasks can't be moved on their own; they follow their parents
+			if !issue.Fields.IssueType.Subtask {
+				keys = append(keys, issue.Key)
+			}
+		}
+		if len(keys) > 0 {
+			if err := moveToSprint(client, sprints, append(keys, *moveTo)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := client.CloseSprint(ctx, sprint.ID); err != nil {
+		return err
+	}
+
+	printSuccess("Closed %s", printHighlight(sprint.Name))
+	if *moveTo == "" && len(incomplete) > 0 {
+		printDim("Incomplete issues were returned to the backlog; use -move next to carry them over")
+	}
+	return nil
+}
+
+// parseSprintRef parses fs allowing a sprint reference before or after the flags
+func parseSprintRef(fs *flag.FlagSet, args []string, def string) (string, error) {
+	var ref []string
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		ref = append(ref, args[0])
+		args = args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return "", err
+	}
+	ref = append(ref, fs.Args()...)
+
+	if len(ref) == 0 {
+		return def, nil
+	}
+	return strings.Join(ref, " "), nil
+}
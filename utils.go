@@ -6,42 +6,28 @@ import (
 	"os"
 	"bufio"
 	"strconv"
+	"context"
+	"maps"
+
+	"github.com/emilsto/jig/jira"
 )
 
 func selectProjectAndBoard(config *Config) (*Project, *Board, error) {
-	if len(config.Projects) == 0 {
-		return nil, nil, fmt.Errorf("no projects configured")
-	}
-
-	jigrcPath := findJigRC()
-	if jigrcPath != "" {
-		jigrc, err := loadJigRC(jigrcPath)
-		if err == nil {
-			// Try to find matching project and board
-			for i := range config.Projects {
-				project := &config.Projects[i]
-
-				// Match by ID or name
-				if (jigrc.ProjectID != "" && project.ID == jigrc.ProjectID) ||
-					(jigrc.ProjectName != "" && project.Name == jigrc.ProjectName) {
-
-					// Find matching board
-					for j := range project.Boards {
-						board := &project.Boards[j]
-
-						// Match by ID or name
-						if (jigrc.BoardID != 0 && board.ID == jigrc.BoardID) ||
-							(jigrc.BoardName != "" && board.Name == jigrc.BoardName) {
-
-							printDim("Using .jigrc from: %s", jigrcPath)
-							return project, board, nil
-						}
-					}
-				}
+	if config.project != "" || config.board != "" {
+		project, board := findProjectAndBoard(config, config.project, config.board)
+		if project != nil && board != nil {
+			origin := config.origins["board"]
+			if origin == "" {
+				origin = config.origins["project"]
 			}
-
-			printWarning(".jigrc found but no matching project/board in config")
+			printDim("Using project and board from: %s", origin)
+			return project, board, nil
 		}
+		printWarning("No project/board in config matches project %q, board %q", config.project, config.board)
+	}
+
+	if len(config.Projects) == 0 {
+		return nil, nil, fmt.Errorf("no projects configured")
 	}
 
 	var selectedProject *Project
@@ -109,17 +95,60 @@ func selectProjectAndBoard(config *Config) (*Project, *Board, error) {
 	return selectedProject, selectedBoard, nil
 }
 
+// findProjectAndBoard matches project and board references (ID or name) against config.toml.
+// A project that isn't configured can still be used given a numeric board ID, which lets
+// jig run from the environment alone
+func findProjectAndBoard(config *Config, projectRef, boardRef string) (*Project, *Board) {
+	matchesBoard := func(board Board) bool {
+		return boardRef == "" || strconv.Itoa(board.ID) == boardRef || strings.EqualFold(board.Name, boardRef)
+	}
+
+	for i := range config.Projects {
+		project := &config.Projects[i]
+		if projectRef != "" && !strings.EqualFold(project.ID, projectRef) && project.Name != projectRef {
+			continue
+		}
+		for j := range project.Boards {
+			if matchesBoard(project.Boards[j]) {
+				return project, &project.Boards[j]
+			}
+		}
+	}
+
+	boardID, err := strconv.Atoi(boardRef)
+	if projectRef == "" || err != nil {
+		return nil, nil
+	}
+	project := &Project{Name: projectRef, ID: projectRef, Boards: []Board{{Name: boardRef, ID: boardID}}}
+	return project, &project.Boards[0]
+}
+
 func handleInitJigrc(config *Config) error {
 	project, board, err := selectProjectAndBoard(config)
 	if err != nil {
 		return fmt.Errorf("failed to select project/board: %v", err)
 	}
 
-	jigrc := &JigRC{
-		ProjectName: project.Name,
-		ProjectID:   project.ID,
-		BoardName:   board.Name,
-		BoardID:     board.ID,
+	// Re-running init keeps the conventions of an existing .jigrc
+	jigrc := &JigRC{}
+	if existing, err := loadJigRC(".jigrc"); err == nil {
+		jigrc = existing
+	}
+	jigrc.ProjectName = project.Name
+	jigrc.ProjectID = project.ID
+	jigrc.BoardName = board.Name
+	jigrc.BoardID = board.ID
+
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Println()
+	conventions, err := confirm(reader, "Set team conventions (branch names, start status, issue types, filters, commit hooks)?", false)
+	if err != nil {
+		return err
+	}
+	if conventions {
+		if err := promptConventions(reader, config, jigrc); err != nil {
+			return err
+		}
 	}
 
 	if err := saveJigRC(jigrc); err != nil {
@@ -133,7 +162,240 @@ func handleInitJigrc(config *Config) error {
 	fmt.Printf("  Board: %s (ID: %d)\n", printHighlight(board.Name), board.ID)
 	fmt.Println()
 
+	// Also removes jig's hooks when they were turned off
+	if conventions || jigrc.Hooks.PrepareCommitMsg || jigrc.Hooks.CommitMsg {
+		return installHooks(jigrc.Hooks)
+	}
+	return nil
+}
+
+// promptConventions asks for the settings a team shares through .jigrc, offering what is
+// configured now as the default
+func promptConventions(reader *bufio.Reader, config *Config, jigrc *JigRC) error {
+	ask := func(question, current string) (string, error) {
+		if current != "" {
+			question += fmt.Sprintf(" [%s]", current)
+		}
+		printPrompt(question)
+		input, err := reader.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		if input = strings.TrimSpace(input); input == "" {
+			return current, nil
+		}
+		if input == "-" {
+			return "", nil
+		}
+		return input, nil
+	}
+	askList := func(question string, current []string) ([]string, error) {
+		answer, err := ask(question, strings.Join(current, ", "))
+		if err != nil || answer == "" {
+			return nil, err
+		}
+		var list []string
+		for _, item := range strings.Split(answer, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		return list, nil
+	}
+
+	printDim("Press Enter to keep the value in brackets, or '-' to clear it")
+	template := config.Git.BranchTemplate
+	if template == "" {
+		template = defaultBranchTemplate
+	}
+	format := config.Git.CommitFormat
+	if format == "" {
+		format = defaultCommitFormat
+	}
+
+	var err error
+	if jigrc.Git.Branchbase, err = ask("Branch prefix / base branch (branchbase)", config.Git.Branchbase); err != nil {
+		return err
+	}
+	if jigrc.Git.BranchTemplate, err = ask("Branch name template ({branchbase}, {key}, {type}, {summary})", template); err != nil {
+		return err
+	}
+	if jigrc.StartStatus, err = ask("Status to move issues to when starting work on them", config.StartStatus); err != nil {
+		return err
+	}
+	if jigrc.IssueTypes, err = askList("Issue types to list (comma-separated, empty for Story, Task, Bug)", config.IssueTypes); err != nil {
+		return err
+	}
+	if jigrc.Table.Columns, err = askList("Extra columns (field names from [fields], comma-separated)", config.Table.Columns); err != nil {
+		return err
+	}
+
+	jigrc.Filters = maps.Clone(jigrc.Filters)
+	for {
+		name, err := ask("Named filter to add, used with -filter NAME (empty when done)", "")
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			break
+		}
+		where, err := askList(fmt.Sprintf("Conditions for %s (field=value, comma-separated)", name), config.Filters[name])
+		if err != nil {
+			return err
+		}
+		if jigrc.Filters == nil {
+			jigrc.Filters = map[string][]string{}
+		}
+		jigrc.Filters[name] = where
+	}
+
+	if jigrc.Git.CommitFormat, err = ask("Commit message format ({key}, {message})", format); err != nil {
+		return err
+	}
+	if jigrc.Hooks.PrepareCommitMsg, err = confirm(reader, "Install a git hook that formats commit messages with the issue key?", jigrc.Hooks.PrepareCommitMsg); err != nil {
+		return err
+	}
+	if jigrc.Hooks.CommitMsg, err = confirm(reader, "Install a git hook that rejects commits without an issue key?", jigrc.Hooks.CommitMsg); err != nil {
+		return err
+	}
+
+	// Defaults aren't written out, so changing them later reaches everyone
+	if jigrc.Git.BranchTemplate == defaultBranchTemplate {
+		jigrc.Git.BranchTemplate = ""
+	}
+	if jigrc.Git.CommitFormat == defaultCommitFormat {
+		jigrc.Git.CommitFormat = ""
+	}
 	return nil
 }
 
 
+
+// selectSprint returns the sprint jig works against for a board, or nil if there is none.
+// An explicit ref (see findSprint) wins; otherwise active sprints are preferred over future
+// ones, and when several are active the user picks one and the choice is kept in .jigrc
+func selectSprint(client *jira.Client, board *Board, reader *bufio.Reader, ref string) (*jira.Sprint, error) {
+	ctx := context.Background()
+
+	if ref != "" {
+		sprints, err := client.GetBoardSprints(ctx, board.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get sprints: %v", err)
+		}
+		return findSprint(sprints, ref)
+	}
+
+	sprints, err := client.GetSprints(ctx, board.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sprints: %v", err)
+	}
+
+	var active []jira.Sprint
+	for _, sprint := range sprints {
+		if sprint.State == "active" {
+			active = append(active, sprint)
+		}
+	}
+
+	switch len(active) {
+	case 0:
+		for i := range sprints {
+			if sprints[i].State == "future" {
+				return &sprints[i], nil
+			}
+		}
+		return nil, nil
+	case 1:
+		return &active[0], nil
+	}
+
+	// The choice is kept with the board, in the .jigrc that names it
+	jigrc, origins, _ := loadMergedJigRC()
+	jigrcPath := findJigRC()
+	if jigrc != nil {
+		if path := origins["board_id"]; path != "" {
+			jigrcPath = path
+		} else if path := origins["board_name"]; path != "" {
+			jigrcPath = path
+		}
+		for i := range active {
+			if jigrc.SprintID != 0 && active[i].ID == jigrc.SprintID {
+				return &active[i], nil
+			}
+		}
+	}
+
+	fmt.Println()
+	printBold("Several sprints are active on %s:", board.Name)
+	for i, sprint := range active {
+		fmt.Printf("  %d. %s (ID: %d) %s%s%s\n", i+1, printHighlight(sprint.Name), sprint.ID,
+			colorDim, sprintDates(sprint), colorReset)
+	}
+
+	fmt.Println()
+	printPrompt("Select sprint number")
+	input, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	selection, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil || selection < 1 || selection > len(active) {
+		return nil, fmt.Errorf("invalid selection")
+	}
+	sprint := &active[selection-1]
+
+	if jigrcPath == "" {
+		printDim("Run jig init to remember this choice")
+		return sprint, nil
+	}
+	if err := rememberSprint(jigrcPath, sprint); err != nil {
+		printWarning("Failed to remember sprint: %v", err)
+	} else {
+		printDim("Saved sprint choice to %s", jigrcPath)
+	}
+	return sprint, nil
+}
+
+// rememberSprint stores the chosen sprint in an existing .jigrc
+func rememberSprint(jigrcPath string, sprint *jira.Sprint) error {
+	jigrc, err := loadJigRC(jigrcPath)
+	if err != nil {
+		return err
+	}
+	jigrc.SprintID = sprint.ID
+	jigrc.SprintName = sprint.Name
+	return writeJigRC(jigrc, jigrcPath)
+}
+
+// loadStatusCategories returns the status categories .jigrc limits the sprint list to, if any
+func loadStatusCategories() []string {
+	jigrc, _, err := loadMergedJigRC()
+	if jigrc == nil || err != nil {
+		return nil
+	}
+	return jigrc.StatusCategories
+}
+
+// confirm asks a yes/no question, returning def when the answer is empty
+func confirm(reader *bufio.Reader, question string, def bool) (bool, error) {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+
+	printPrompt(fmt.Sprintf("%s [%s]", question, hint))
+	input, err := reader.ReadString('\n')
+	if err != nil {
+		return false, err
+	}
+
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "":
+		return def, nil
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
@@ -0,0 +1,440 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emilsto/jig/jira"
+)
+
+// Jira's default time tracking settings: 8 hour days and 5 day weeks
+const (
+	workDay  = 8 * time.Hour
+	workWeek = 5 * workDay
+)
+
+var durationPart = regexp.MustCompile(`^(\d+(?:\.\d+)?)([wdhm])`)
+
+// parseWorklogDuration parses Jira-style durations such as "1h30m", "1h 30m", "2d" or "1.5h"
+func parseWorklogDuration(input string) (time.Duration, error) {
+	s := strings.ToLower(strings.ReplaceAll(input, " ", ""))
+	if s == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+
+	var total time.Duration
+	for s != "" {
+		m := durationPart.FindStringSubmatch(s)
+		if m == nil {
+			return 0, fmt.Errorf("invalid duration %q, expected e.g. 1h30m, 45m or 2d", input)
+		}
+		value, _ := strconv.ParseFloat(m[1], 64)
+
+		unit := time.Minute
+		switch m[2] {
+		case "w":
+			unit = workWeek
+		case "d":
+			unit = workDay
+		case "h":
+			unit = time.Hour
+		}
+		total += time.Duration(value * float64(unit))
+		s = s[len(m[0]):]
+	}
+
+	if total < time.Minute {
+		return 0, fmt.Errorf("duration must be at least 1m")
+	}
+	return total, nil
+}
+
+// formatWorklogDuration renders a duration as hours and minutes, e.g. "1h 30m"
+func formatWorklogDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	}
+}
+
+// noteToADF converts an optional worklog note to ADF
+func noteToADF(note string) *jira.ADFNode {
+	if strings.TrimSpace(note) == "" {
+		return nil
+	}
+	return jira.MarkdownToADF(note)
+}
+
+// runLogCommand implements `jig log KEY 1h30m ["note"]`
+func runLogCommand(args []string) error {
+	fs := flag.NewFlagSet("log", flag.ExitOnError)
+	date := fs.String("d", "", "Date the work was done (YYYY-MM-DD), defaults to now")
+
+	issueKey, rest, err := parseWithKey(fs, args)
+	if err != nil || len(rest) == 0 {
+		return fmt.Errorf("usage: jig log KEY DURATION [note] [-d YYYY-MM-DD]")
+	}
+
+	spent, err := parseWorklogDuration(rest[0])
+	if err != nil {
+		return err
+	}
+
+	// Flags may also follow the duration or the note, so keep parsing after each word
+	var words []string
+	for rest = rest[1:]; len(rest) > 0; rest = rest[1:] {
+		if err := fs.Parse(rest); err != nil {
+			return err
+		}
+		if rest = fs.Args(); len(rest) == 0 {
+			break
+		}
+		words = append(words, rest[0])
+	}
+	note := strings.Join(words, " ")
+
+	started := time.Now().Add(-spent)
+	if *date != "" {
+		day, err := time.ParseInLocation("2006-01-02", *date, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid date %q, expected YYYY-MM-DD", *date)
+		}
+		started = day.Add(9 * time.Hour)
+	}
+
+	_, client, err := loadClient()
+	if err != nil {
+		return err
+	}
+
+	if err := client.AddWorklog(context.Background(), issueKey, spent, started, noteToADF(note)); err != nil {
+		return err
+	}
+
+	printSuccess("Logged %s on %s", formatWorklogDuration(spent), printHighlight(issueKey))
+	return nil
+}
+
+func handleLogWork(ctx *actionContext, issue jira.Issue) error {
+	printPrompt("Time spent (e.g. 1h30m, 45m, 1d)")
+	input, err := ctx.reader.ReadString('\n')
+	if err != nil {
+		return err
+	}
+
+	spent, err := parseWorklogDuration(input)
+	if err != nil {
+		return err
+	}
+
+	printPrompt("Work description (optional)")
+	note, err := ctx.reader.ReadString('\n')
+	if err != nil {
+		return err
+	}
+
+	printInfo("Logging %s on %s...", formatWorklogDuration(spent), issue.Key)
+	if err := ctx.jiraClient.AddWorklog(context.Background(), issue.Key, spent, time.Now().Add(-spent), noteToADF(note)); err != nil {
+		return err
+	}
+
+	printSuccess("Logged %s on %s", formatWorklogDuration(spent), printHighlight(issue.Key))
+	return nil
+}
+
+// --- Timer ---
+
+// timerState is persisted to disk so a running timer survives restarts
+type timerState struct {
+	IssueKey string    `json:"issue_key"`
+	Started  time.Time `json:"started"`
+	Note     string    `json:"note,omitempty"`
+}
+
+func timerPath() (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "timer.json"), nil
+}
+
+// loadTimer returns the running timer, or nil when none is running
+func loadTimer() (*timerState, error) {
+	path, err := timerPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	state := &timerState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("corrupt timer state in %s: %v", path, err)
+	}
+	return state, nil
+}
+
+func saveTimer(state *timerState) error {
+	path, err := timerPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create state directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+func clearTimer() error {
+	path, err := timerPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// runTimerCommand implements `jig timer start KEY [note] | stop [note] | status | cancel`
+func runTimerCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: jig timer start KEY [note] | stop [note] | status | cancel")
+	}
+
+	state, err := loadTimer()
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "start":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: jig timer start KEY [note]")
+		}
+		if state != nil {
+			return fmt.Errorf("timer already running for %s since %s; stop or cancel it first",
+				state.IssueKey, state.Started.Format("15:04"))
+		}
+		state = &timerState{
+			IssueKey: strings.ToUpper(args[1]),
+			Started:  time.Now(),
+			Note:     strings.Join(args[2:], " "),
+		}
+		if err := saveTimer(state); err != nil {
+			return err
+		}
+		printSuccess("Timer started for %s", printHighlight(state.IssueKey))
+
+	case "stop":
+		if state == nil {
+			return fmt.Errorf("no timer running")
+		}
+		note := state.Note
+		if len(args) > 1 {
+			note = strings.Join(args[1:], " ")
+		}
+
+		// Jira rejects worklogs shorter than a minute
+		elapsed := time.Since(state.Started).Round(time.Minute)
+		if elapsed < time.Minute {
+			elapsed = time.Minute
+		}
+
+		_, client, err := loadClient()
+		if err != nil {
+			return err
+		}
+		if err := client.AddWorklog(context.Background(), state.IssueKey, elapsed, state.Started, noteToADF(note)); err != nil {
+			return err
+		}
+		if err := clearTimer(); err != nil {
+			return err
+		}
+		printSuccess("Logged %s on %s", formatWorklogDuration(elapsed), printHighlight(state.IssueKey))
+
+	case "status":
+		if state == nil {
+			fmt.Println("No timer running")
+			return nil
+		}
+		fmt.Printf("%s: %s (since %s)\n", printHighlight(state.IssueKey),
+			formatWorklogDuration(time.Since(state.Started)), state.Started.Format("Mon 15:04"))
+		if state.Note != "" {
+			printDim("  %s", state.Note)
+		}
+
+	case "cancel":
+		if state == nil {
+			return fmt.Errorf("no timer running")
+		}
+		if err := clearTimer(); err != nil {
+			return err
+		}
+		printWarning("Timer for %s discarded", state.IssueKey)
+
+	default:
+		return fmt.Errorf("unknown timer command %q", args[0])
+	}
+
+	return nil
+}
+
+// --- Weekly report ---
+
+// startOfWeek returns Monday 00:00 of the week containing t
+func startOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	day := t.AddDate(0, 0, -offset)
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// weekday returns the index of t's calendar day in the week beginning at start, or -1.
+// Days are compared by date, as a week with a DST change isn't 7*24 hours long
+func weekday(start, t time.Time) int {
+	y, m, d := t.In(start.Location()).Date()
+	for i := 0; i < 7; i++ {
+		if dy, dm, dd := start.AddDate(0, 0, i).Date(); dy == y && dm == m && dd == d {
+			return i
+		}
+	}
+	return -1
+}
+
+// runWorklogCommand implements `jig worklog --week`, a per issue and day report of my logged time
+func runWorklogCommand(args []string) error {
+	fs := flag.NewFlagSet("worklog", flag.ExitOnError)
+	fs.Bool("week", true, "Report the current week")
+	last := fs.Bool("last", false, "Report the previous week instead")
+	fs.Parse(args)
+
+	start := startOfWeek(time.Now())
+	if *last {
+		start = start.AddDate(0, 0, -7)
+	}
+	end := start.AddDate(0, 0, 7)
+
+	_, client, err := loadClient()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	accountID, err := client.CurrentAccountID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get current user: %v", err)
+	}
+
+	jql := fmt.Sprintf(`worklogAuthor = currentUser() AND worklogDate >= "%s" AND worklogDate < "%s"`,
+		start.Format("2006-01-02"), end.Format("2006-01-02"))
+	printInfo("Fetching worklogs for week of %s...", start.Format("Jan 2"))
+	issues, err := client.SearchIssues(ctx, jql, "summary")
+	if err != nil {
+		return err
+	}
+
+	type row struct {
+		issue jira.Issue
+		days  [7]time.Duration
+		total time.Duration
+	}
+	var rows []row
+	var dayTotals [7]time.Duration
+	var total time.Duration
+
+	for _, issue := range issues {
+		worklogs, err := client.GetWorklogs(ctx, issue.Key, start)
+		if err != nil {
+			return err
+		}
+
+		r := row{issue: issue}
+		for _, w := range worklogs {
+			if w.Author.ID() != accountID {
+				continue
+			}
+			started, err := w.StartedAt()
+			if err != nil || started.Before(start) || !started.Before(end) {
+				continue
+			}
+			day := weekday(start, started)
+			if day < 0 {
+				continue
+			}
+			spent := time.Duration(w.TimeSpentSeconds) * time.Second
+			r.days[day] += spent
+			r.total += spent
+			dayTotals[day] += spent
+			total += spent
+		}
+		if r.total > 0 {
+			rows = append(rows, r)
+		}
+	}
+
+	if len(rows) == 0 {
+		fmt.Println("\nNo time logged this week")
+		return nil
+	}
+	slices.SortFunc(rows, func(a, b row) int { return int(b.total - a.total) })
+
+	cell := func(d time.Duration) string {
+		if d == 0 {
+			return colorDim + fmt.Sprintf("%7s", "·") + colorReset
+		}
+		return fmt.Sprintf("%7s", formatWorklogDuration(d))
+	}
+
+	fmt.Println()
+	printBold("Logged time, week of %s:", start.Format("Mon Jan 2"))
+	fmt.Printf("%s%-12s %-30s", colorBold, "KEY", "SUMMARY")
+	for i := 0; i < 7; i++ {
+		fmt.Printf(" %7s", start.AddDate(0, 0, i).Format("Mon 2"))
+	}
+	fmt.Printf(" %8s%s\n", "TOTAL", colorReset)
+
+	for _, r := range rows {
+		fmt.Printf("%s%-12s%s %-30s", colorCyan, r.issue.Key, colorReset, truncate(r.issue.Fields.Summary, 30))
+		for _, d := range r.days {
+			fmt.Printf(" %s", cell(d))
+		}
+		fmt.Printf(" %8s\n", formatWorklogDuration(r.total))
+	}
+
+	fmt.Printf("%s%-43s", colorBold, "TOTAL")
+	for _, d := range dayTotals {
+		fmt.Printf(" %7s", formatWorklogDuration(d))
+	}
+	fmt.Printf(" %8s%s\n", formatWorklogDuration(total), colorReset)
+	return nil
+}